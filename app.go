@@ -2,7 +2,10 @@ package main
 
 import (
 	"career-compass-go/config"
+	"career-compass-go/pkg/digest"
+	"career-compass-go/pkg/linkcheck"
 	"career-compass-go/pkg/logging"
+	"career-compass-go/pkg/predictor"
 	"career-compass-go/pkg/setting"
 	"career-compass-go/routers"
 	"career-compass-go/utils"
@@ -16,6 +19,7 @@ import (
 func init() {
 	logging.Setup()
 	setting.Setup()
+	predictor.Setup()
 }
 
 func main() {
@@ -30,9 +34,15 @@ func main() {
 		return
 	}
 
+	// Periodically check the learning resource links for rot
+	go linkcheck.Start()
+
+	// Email the followers of skills their daily or weekly digests
+	go digest.Start()
+
 	c := cors.New(cors.Options{
 		AllowedOrigins: []string{"*"},
-		AllowedHeaders: []string{"API-Token", "authorization", "Access-Control-Allow-Origin", "content-type", "Origin", "X-Requested-With", "Accept"},
+		AllowedHeaders: []string{"API-Token", "authorization", "Access-Control-Allow-Origin", "content-type", "Origin", "X-Requested-With", "Accept", "Last-Event-ID"},
 		AllowedMethods: []string{"GET", "PUT", "POST", "DELETE"},
 		ExposedHeaders: []string{"API-Token-Expiry"},
 		MaxAge:         5,
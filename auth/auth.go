@@ -32,3 +32,42 @@ func GenerateToken(userID, email string) (string, error) {
 
 	return tokenString, nil
 }
+
+// GenerateUnsubscribeToken generates a signed, non-expiring token letting the user unsubscribe from digests in one click
+func GenerateUnsubscribeToken(userID string) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256,
+		jwt.MapClaims{
+			"userID":  userID,
+			"purpose": config.UnsubscribePurpose,
+		})
+
+	tokenString, err := token.SignedString([]byte(config.JWTSecret))
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error creating signed unsubscribe token -> %s", err.Error()))
+		return "", err
+	}
+
+	return tokenString, nil
+}
+
+// VerifyUnsubscribeToken verifies an unsubscribe token and returns the user it was issued for
+func VerifyUnsubscribeToken(tokenString string) (string, error) {
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (any, error) {
+		return []byte(config.JWTSecret), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil {
+		return "", err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid || claims["purpose"] != config.UnsubscribePurpose {
+		return "", jwt.ErrTokenInvalidClaims
+	}
+
+	userID, ok := claims["userID"].(string)
+	if !ok {
+		return "", jwt.ErrTokenInvalidClaims
+	}
+
+	return userID, nil
+}
@@ -0,0 +1,28 @@
+// Command reputation recalculates the reputation and badges of every user from scratch
+// out of their questions, answers, votes and accepted answers.
+//
+// Run it from the repository root after changing the reputation rules or repairing Q&A data:
+//
+//	go run ./cmd/reputation
+package main
+
+import (
+	"career-compass-go/config"
+	"career-compass-go/pkg/logging"
+	"career-compass-go/pkg/setting"
+	"career-compass-go/service"
+	"log"
+)
+
+func main() {
+	logging.Setup()
+	setting.SetupMongo()
+	defer setting.CloseMongoClient(config.MongoClient)
+
+	var reputation service.Reputation
+
+	err := reputation.Recalculate(nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
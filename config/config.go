@@ -5,7 +5,9 @@ import (
 	"go.mongodb.org/mongo-driver/mongo"
 	"html/template"
 	"log"
+	"path/filepath"
 	"strconv"
+	"time"
 )
 
 var (
@@ -19,6 +21,20 @@ var (
 	SkillCollection    *mongo.Collection
 	QuestionCollection *mongo.Collection
 	AnswerCollection   *mongo.Collection
+	CompanyCollection  *mongo.Collection
+	ResourceCollection *mongo.Collection
+	RevisionCollection *mongo.Collection
+	CommentCollection  *mongo.Collection
+
+	ReputationCollection *mongo.Collection
+	FlagCollection       *mongo.Collection
+	ModerationCollection *mongo.Collection
+
+	SubscriptionCollection *mongo.Collection
+	NotificationCollection *mongo.Collection
+	BookmarkCollection     *mongo.Collection
+
+	AssessmentCollection *mongo.Collection
 
 	Templates *template.Template
 
@@ -29,13 +45,36 @@ var (
 
 	JWTSecret string
 
-	MLServerURL string
+	MLServerURL        string
+	MLTimeout          time.Duration
+	MLRetries          int
+	MLRetryBackoff     time.Duration
+	MLBreakerThreshold int
+	MLBreakerCooldown  time.Duration
+	Predictor          string
+	PredictorFallback  bool
+	LocalModelPath     string
+	RoleAliasesPath    string
+	RoleAliases        map[string]string
+
+	LinkCheckInterval    time.Duration
+	LinkCheckTimeout     time.Duration
+	LinkCheckConcurrency int
+
+	FlagHideThreshold int64
+
+	APIBaseURL          string
+	DigestCheckInterval time.Duration
 )
 
 func init() {
 	viper.AutomaticEnv()
 	viper.SetConfigName("app")
 	viper.AddConfigPath("config/")
+
+	// Tests run from their package directory below the project root
+	viper.AddConfigPath("../config/")
+	viper.AddConfigPath("../../config/")
 	err := viper.ReadInConfig()
 	if err != nil {
 		log.Fatal(err)
@@ -43,7 +82,8 @@ func init() {
 	ViperConfig = viper.GetViper()
 
 	// Initialize and parse the mailer template files
-	Templates = template.Must(template.ParseGlob("mailer/templates/*.html"))
+	rootDir := filepath.Dir(filepath.Dir(viper.ConfigFileUsed()))
+	Templates = template.Must(template.ParseGlob(filepath.Join(rootDir, "mailer/templates/*.html")))
 
 	MongoDBName = ViperConfig.GetString("DB_NAME")
 
@@ -55,4 +95,71 @@ func init() {
 	JWTSecret = ViperConfig.GetString("JWT_SECRET")
 
 	MLServerURL = ViperConfig.GetString("ML_SERVER_URL")
+	MLTimeout = ViperConfig.GetDuration("ML_TIMEOUT")
+	MLRetries = ViperConfig.GetInt("ML_RETRIES")
+	MLRetryBackoff = ViperConfig.GetDuration("ML_RETRY_BACKOFF")
+	MLBreakerThreshold = ViperConfig.GetInt("ML_BREAKER_THRESHOLD")
+	MLBreakerCooldown = ViperConfig.GetDuration("ML_BREAKER_COOLDOWN")
+
+	// A zero timeout fails every attempt at once and a zero threshold opens the breaker on the first failure
+	if MLTimeout <= 0 {
+		log.Printf("ML_TIMEOUT must be positive, defaulting to %s", DefaultMLTimeout)
+		MLTimeout = DefaultMLTimeout
+	}
+
+	if MLRetries < 0 {
+		log.Printf("ML_RETRIES must not be negative, defaulting to %d", DefaultMLRetries)
+		MLRetries = DefaultMLRetries
+	}
+
+	if MLRetryBackoff <= 0 {
+		log.Printf("ML_RETRY_BACKOFF must be positive, defaulting to %s", DefaultMLRetryBackoff)
+		MLRetryBackoff = DefaultMLRetryBackoff
+	}
+
+	if MLBreakerThreshold <= 0 {
+		log.Printf("ML_BREAKER_THRESHOLD must be positive, defaulting to %d", DefaultMLBreakerThreshold)
+		MLBreakerThreshold = DefaultMLBreakerThreshold
+	}
+
+	if MLBreakerCooldown <= 0 {
+		log.Printf("ML_BREAKER_COOLDOWN must be positive, defaulting to %s", DefaultMLBreakerCooldown)
+		MLBreakerCooldown = DefaultMLBreakerCooldown
+	}
+
+	Predictor = ViperConfig.GetString("PREDICTOR")
+	PredictorFallback = ViperConfig.GetBool("PREDICTOR_FALLBACK")
+	LocalModelPath = ViperConfig.GetString("LOCAL_MODEL_PATH")
+	RoleAliasesPath = ViperConfig.GetString("ROLE_ALIASES_PATH")
+
+	LinkCheckInterval = ViperConfig.GetDuration("LINK_CHECK_INTERVAL")
+	LinkCheckTimeout = ViperConfig.GetDuration("LINK_CHECK_TIMEOUT")
+	LinkCheckConcurrency = ViperConfig.GetInt("LINK_CHECK_CONCURRENCY")
+
+	// A missing interval would check every link in a tight loop
+	if LinkCheckInterval <= 0 {
+		log.Printf("LINK_CHECK_INTERVAL must be positive, defaulting to %s", DefaultLinkCheckInterval)
+		LinkCheckInterval = DefaultLinkCheckInterval
+	}
+
+	if LinkCheckTimeout <= 0 {
+		log.Printf("LINK_CHECK_TIMEOUT must be positive, defaulting to %s", DefaultLinkCheckTimeout)
+		LinkCheckTimeout = DefaultLinkCheckTimeout
+	}
+
+	FlagHideThreshold = ViperConfig.GetInt64("FLAG_HIDE_THRESHOLD")
+
+	// A threshold of zero would hide every post on its first flag
+	if FlagHideThreshold <= 0 {
+		log.Printf("FLAG_HIDE_THRESHOLD must be positive, defaulting to %d", DefaultFlagHideThreshold)
+		FlagHideThreshold = DefaultFlagHideThreshold
+	}
+
+	APIBaseURL = ViperConfig.GetString("API_BASE_URL")
+	DigestCheckInterval = ViperConfig.GetDuration("DIGEST_CHECK_INTERVAL")
+
+	if DigestCheckInterval <= 0 {
+		log.Printf("DIGEST_CHECK_INTERVAL must be positive, defaulting to %s", DefaultDigestCheckInterval)
+		DigestCheckInterval = DefaultDigestCheckInterval
+	}
 }
@@ -6,15 +6,143 @@ const (
 	TTLIndexExpirySeconds = 10 * 60
 	OTPExpiryTime         = time.Minute * 10
 
-	OTPChars  = "1234567890"
-	OPTLength = 6
-	MailOTP   = "MailOTP"
+	OTPChars   = "1234567890"
+	OPTLength  = 6
+	MailOTP    = "MailOTP"
+	MailDigest = "MailDigest"
 
-	UserRole = "user"
+	DigestNone          = "none"
+	DigestDaily         = "daily"
+	DigestWeekly        = "weekly"
+	DigestQuestionLimit = 10
+	UnsubscribePurpose  = "digest-unsubscribe"
+
+	DefaultDigestCheckInterval = time.Hour
+
+	UserRole      = "user"
+	ModeratorRole = "moderator"
+
+	QuestionPost = "question"
+	AnswerPost   = "answer"
+	CommentPost  = "comment"
+
+	SubscriptionQuestion = "question"
+	SubscriptionSkill    = "skill"
+
+	NotificationQuestion = "question"
+	NotificationAnswer   = "answer"
+	NotificationComment  = "comment"
+	NotificationAccepted = "accepted"
+	NotificationStatus   = "status"
+	NotificationMention  = "mention"
+
+	// MentionProfilePath is the frontend profile route the mentions link to, relative so the frontend resolves it
+	MentionProfilePath = "/users/%s"
+
+	EventQuestion     = "question"
+	EventAnswer       = "answer"
+	EventQuestionVote = "question-vote"
+	EventAnswerVote   = "answer-vote"
+	EventNotification = "notification"
+
+	EventHistorySize       = 1000
+	EventBufferSize        = 64
+	EventHeartbeatInterval = 15 * time.Second
+
+	BookmarkRole          = "role"
+	BookmarkSkill         = "skill"
+	BookmarkQuestion      = "question"
+	BookmarkResource      = "resource"
+	DefaultBookmarkFolder = "Saved"
+
+	RemotePredictor = "remote"
+	LocalPredictor  = "local"
+
+	RestClientTimeout = 30 * time.Second
+	MLResponseLimit   = 1 << 20
+
+	SkillProficiencyReputation = 50
+	SkillProficiencyRating     = 4
+	RecommendedResourceLimit   = 3
+
+	FlagPending  = "pending"
+	FlagResolved = "resolved"
+
+	ModerationHide      = "hide"
+	ModerationDelete    = "delete"
+	ModerationDismiss   = "dismiss"
+	ModerationWarn      = "warn"
+	ModerationAutoHide  = "auto-hide"
+	ModerationSuspend   = "suspend"
+	ModerationUnsuspend = "unsuspend"
+	ModerationMerge     = "merge"
+	ModerationPageSize  = 50
+
+	DuplicateThreshold  = 0.5
+	DuplicateLimit      = 5
+	DuplicateCorpusSize = 1000
+
+	CommentPreviewSize = 3
+	TagSuggestionLimit = 10
 
 	RoleSearch  = "role"
 	SkillSearch = "skill"
 
+	SalaryPeriodYearly  = "yearly"
+	SalaryPeriodMonthly = "monthly"
+	SalaryPeriodHourly  = "hourly"
+	SalarySourceLegacy  = "legacy"
+
+	SalarySort     = "salary"
+	VotesSort      = "votes"
+	NewestSort     = "newest"
+	UnansweredSort = "unanswered"
+	SortDescending = "desc"
+
+	ResourceTypeVideo   = "video"
+	ResourceTypeWebsite = "website"
+	ResourceTypeCourse  = "course"
+
+	DefaultMLTimeout          = 10 * time.Second
+	DefaultMLRetries          = 2
+	DefaultMLRetryBackoff     = 200 * time.Millisecond
+	DefaultMLBreakerThreshold = 5
+	DefaultMLBreakerCooldown  = 30 * time.Second
+
+	DefaultLinkCheckInterval = 24 * time.Hour
+	DefaultLinkCheckTimeout  = 10 * time.Second
+
+	DefaultFlagHideThreshold = 3
+
+	LinkCheckHistory    = 10
+	LinkBrokenThreshold = 2
+	LinkCheckBodyLimit  = 1024
+
+	// MaxQuestionTags matches the max=5 binding on the question tags
+	MaxQuestionTags = 5
+
+	AssessmentTrendLimit = 100
+
+	MinCompareRoles = 2
+	MaxCompareRoles = 3
+
+	VoteUp   = 1
+	VoteNone = 0
+	VoteDown = -1
+
+	QuestionUpvoteReputation = 5
+	AnswerUpvoteReputation   = 10
+	AnswerDownvoteReputation = -2
+	AcceptedAnswerReputation = 15
+
+	FirstAnswerBadge          = "first-answer"
+	AcceptedAnswersBadge      = "ten-accepted-answers"
+	AcceptedAnswersBadgeCount = 10
+	SkillExpertBadge          = "skill-expert"
+	SkillExpertReputation     = 200
+
+	LeaderboardSize = 20
+
 	QuestionUnresolved = "Unresolved"
 	QuestionResolved   = "Resolved"
 )
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"career-compass-go/pkg/logging"
+	"career-compass-go/service"
+	"career-compass-go/utils"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"net/http"
+	"runtime"
+	"time"
+)
+
+// GetAssessments is the handler to get the assessment history of the current user, newest first
+func GetAssessments(c *gin.Context) {
+	var query service.AssessmentQuery
+
+	err := c.ShouldBindQuery(&query)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing assessment query -> %s", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userObjectID, err := primitive.ObjectIDFromHex(c.GetString("userID"))
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing userID to object -> %s", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	filters := []bson.E{{"user_id", userObjectID}}
+
+	var assessment service.Assessment
+
+	assessments, err := assessment.GetAll(filters, options.Find().SetSkip((query.Page-1)*query.Limit).SetLimit(query.Limit))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	total, err := assessment.Count(filters)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"assessments": assessments, "page": query.Page, "limit": query.Limit, "total": total}})
+}
+
+// GetAssessmentTrend is the handler to get how the current user's ratings and predicted role changed over time
+func GetAssessmentTrend(c *gin.Context) {
+	userObjectID, err := primitive.ObjectIDFromHex(c.GetString("userID"))
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing userID to object -> %s", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	var assessment service.Assessment
+
+	trend, err := assessment.GetTrend(userObjectID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": trend})
+}
+
+// saveAssessment records the submitted ratings and their prediction in the user's assessment history
+func saveAssessment(userID primitive.ObjectID, ratingsData service.RatingsData, prediction, modelVersion string) (*service.Assessment, error) {
+	assessment := service.Assessment{
+		UserID:       userID,
+		Ratings:      ratingsData,
+		Prediction:   prediction,
+		ModelVersion: modelVersion,
+		CreatedAt:    time.Now(),
+	}
+
+	err := assessment.Add()
+	if err != nil {
+		return nil, err
+	}
+
+	return &assessment, nil
+}
@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"career-compass-go/config"
+	"career-compass-go/pkg/logging"
+	"career-compass-go/service"
+	"career-compass-go/utils"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"net/http"
+	"runtime"
+	"strings"
+)
+
+// bookmarkMove holds the folder a bookmark is moved into
+type bookmarkMove struct {
+	Folder string `json:"folder" binding:"required,max=50"`
+}
+
+// AddBookmark is the handler for the user to save a role, skill, question or resource into a folder
+func AddBookmark(c *gin.Context) {
+	var bookmark service.Bookmark
+
+	err := c.ShouldBind(&bookmark)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing the request body -> %s", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	bookmark.UserID, err = primitive.ObjectIDFromHex(c.GetString("userID"))
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing userID to object -> %s", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	bookmark.Folder = strings.TrimSpace(bookmark.Folder)
+	if bookmark.Folder == "" {
+		bookmark.Folder = config.DefaultBookmarkFolder
+	}
+
+	exists, err := bookmark.TargetExists()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !exists {
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("The bookmarked %s was not found", bookmark.TargetType)})
+		return
+	}
+
+	err = bookmark.Save()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": bookmark})
+}
+
+// GetBookmarks is the handler to list the current user's bookmarks, optionally of a folder or item type
+func GetBookmarks(c *gin.Context) {
+	userObjectID, err := primitive.ObjectIDFromHex(c.GetString("userID"))
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing userID to object -> %s", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	filters := []bson.E{{"user_id", userObjectID}}
+
+	if folder := strings.TrimSpace(c.Query("folder")); folder != "" {
+		filters = append(filters, bson.E{"folder", folder})
+	}
+
+	if targetType := c.Query("type"); targetType != "" {
+		filters = append(filters, bson.E{"target_type", targetType})
+	}
+
+	var bookmark service.Bookmark
+
+	bookmarks, err := bookmark.GetAll(filters)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": bookmarks})
+}
+
+// GetBookmarkFolders is the handler to list the current user's bookmark folders with their item counts
+func GetBookmarkFolders(c *gin.Context) {
+	userObjectID, err := primitive.ObjectIDFromHex(c.GetString("userID"))
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing userID to object -> %s", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	var bookmark service.Bookmark
+
+	folders, err := bookmark.GetFolders(userObjectID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": folders})
+}
+
+// MoveBookmark is the handler for the user to move the bookmark of the item in the request path into another folder
+func MoveBookmark(c *gin.Context) {
+	var move bookmarkMove
+
+	err := c.ShouldBind(&move)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing the request body -> %s", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userObjectID, targetObjectID, ok := getBookmarkIDs(c)
+	if !ok {
+		return
+	}
+
+	var bookmark service.Bookmark
+
+	err = bookmark.Move(userObjectID, targetObjectID, strings.TrimSpace(move.Folder))
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Bookmark not found"})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": "Bookmark moved successfully"})
+}
+
+// DeleteBookmark is the handler for the user to remove the bookmark of the item in the request path
+func DeleteBookmark(c *gin.Context) {
+	userObjectID, targetObjectID, ok := getBookmarkIDs(c)
+	if !ok {
+		return
+	}
+
+	var bookmark service.Bookmark
+
+	err := bookmark.Delete(userObjectID, targetObjectID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Bookmark not found"})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": "Bookmark removed successfully"})
+}
+
+// getBookmarkIDs parses the current user and the bookmarked item in the request path
+func getBookmarkIDs(c *gin.Context) (primitive.ObjectID, primitive.ObjectID, bool) {
+	targetObjectID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing targetID to object -> %s", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return primitive.NilObjectID, primitive.NilObjectID, false
+	}
+
+	userObjectID, err := primitive.ObjectIDFromHex(c.GetString("userID"))
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing userID to object -> %s", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return primitive.NilObjectID, primitive.NilObjectID, false
+	}
+
+	return userObjectID, targetObjectID, true
+}
+
+// getBookmarked gets which of the given items the current user has bookmarked
+func getBookmarked(c *gin.Context, targetIDs ...primitive.ObjectID) (map[primitive.ObjectID]bool, bool) {
+	userObjectID, err := primitive.ObjectIDFromHex(c.GetString("userID"))
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing userID to object -> %s", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, false
+	}
+
+	var bookmark service.Bookmark
+
+	bookmarked, err := bookmark.GetBookmarkedIDs(userObjectID, targetIDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, false
+	}
+
+	return bookmarked, true
+}
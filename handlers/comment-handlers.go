@@ -0,0 +1,225 @@
+package handlers
+
+import (
+	"career-compass-go/config"
+	"career-compass-go/pkg/logging"
+	"career-compass-go/service"
+	"career-compass-go/utils"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"net/http"
+	"runtime"
+	"time"
+)
+
+// AddComment is the handler to add a new comment or reply to a question or answer
+func AddComment(c *gin.Context) {
+	var comment service.Comment
+
+	err := c.ShouldBind(&comment)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing the request body -> %s", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Check that the commented post exists
+	filters := []bson.E{
+		{"_id", comment.PostID},
+	}
+
+	var (
+		question service.Question
+		answer   service.Answer
+	)
+
+	switch comment.PostType {
+	case config.QuestionPost:
+		err = question.Get(filters)
+	case config.AnswerPost:
+		err = answer.Get(filters)
+		if err == nil {
+			err = question.Get([]bson.E{{"_id", answer.QuestionID}})
+		}
+	}
+
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("The commented %s was not found", comment.PostType)})
+			return
+		}
+
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error getting %s [%s] -> %s", comment.PostType, comment.PostID.Hex(), err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	comment.AncestorIDs = []primitive.ObjectID{}
+
+	var parentAuthorID primitive.ObjectID
+
+	// Replies inherit the thread of their parent comment
+	if !comment.ParentID.IsZero() {
+		var parent service.Comment
+
+		err = parent.Get([]bson.E{{"_id", comment.ParentID}, {"post_id", comment.PostID}})
+		if err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "The parent comment was not found"})
+				return
+			}
+
+			logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error getting parent comment [%s] -> %s", comment.ParentID.Hex(), err.Error()))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		comment.AncestorIDs = append(parent.AncestorIDs, parent.ID)
+		parentAuthorID = parent.UserID
+	}
+
+	user, ok := getCurrentUser(c)
+	if !ok {
+		return
+	}
+
+	currTime := time.Now()
+
+	comment.UserID = user.ID
+	comment.UserName = user.Username
+	comment.CreatedAt = currTime
+	comment.UpdatedAt = currTime
+
+	err = comment.Add()
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error creating comment details -> %s", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Besides the question's followers, the commented answer's author and the replied-to commenter hear about it
+	notification := service.Notification{
+		Type:       config.NotificationComment,
+		ActorID:    comment.UserID,
+		ActorName:  comment.UserName,
+		SkillID:    question.SkillID,
+		QuestionID: question.ID,
+		PostID:     comment.ID,
+		Message:    fmt.Sprintf("%s commented on \"%s\"", comment.UserName, question.Title),
+	}
+
+	go notify(notification, []primitive.ObjectID{question.ID}, answer.UserID, parentAuthorID)
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"commentID": comment.ID}})
+}
+
+// GetComments is the handler for fetching the threaded comments of a question or answer
+func GetComments(c *gin.Context) {
+	postID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing postID to object -> %s", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var comment service.Comment
+
+	comments, err := comment.GetAll([]bson.E{{"post_id", postID}, {"hidden", bson.D{{"$ne", true}}}})
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error getting comments of post [%s] -> %s", postID.Hex(), err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": service.BuildThreads(comments)})
+}
+
+// EditComment is the handler for the author or a moderator to edit a comment
+func EditComment(c *gin.Context) {
+	var edit postEdit
+
+	err := c.ShouldBind(&edit)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing the request body -> %s", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	comment, ok := getComment(c)
+	if !ok {
+		return
+	}
+
+	_, ok = authorizeAuthor(c, comment.UserID)
+	if !ok {
+		return
+	}
+
+	updateFields := bson.D{
+		{"$set", bson.D{
+			{"content", edit.Content},
+			{"updated_at", time.Now()},
+		}},
+	}
+
+	err = comment.Update(comment.ID, updateFields)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error updating comment details -> %s", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": "Comment updated successfully"})
+}
+
+// DeleteComment is the handler for the author or a moderator to delete a comment with its replies
+func DeleteComment(c *gin.Context) {
+	comment, ok := getComment(c)
+	if !ok {
+		return
+	}
+
+	_, ok = authorizeAuthor(c, comment.UserID)
+	if !ok {
+		return
+	}
+
+	err := comment.Delete(comment.ID)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error deleting comment [%s] -> %s", comment.ID.Hex(), err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": "Comment deleted successfully"})
+}
+
+// getComment fetches the comment in the request path
+func getComment(c *gin.Context) (*service.Comment, bool) {
+	commentID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing commentID to object -> %s", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+
+	var comment service.Comment
+
+	err = comment.Get([]bson.E{{"_id", commentID}})
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Comment not found"})
+			return nil, false
+		}
+
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error getting comment [%s] -> %s", commentID.Hex(), err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, false
+	}
+
+	return &comment, true
+}
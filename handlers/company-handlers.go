@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"career-compass-go/pkg/logging"
+	"career-compass-go/service"
+	"career-compass-go/utils"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"net/http"
+	"runtime"
+)
+
+// CreateCompany is the handler for creating new company entry
+func CreateCompany(c *gin.Context) {
+	var company service.Company
+
+	err := c.ShouldBind(&company)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing the request body -> %s", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err = company.Create()
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Company with this name already exists"})
+			return
+		}
+
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error creating company document -> %s", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"companyID": company.ID}})
+}
+
+// GetAllCompanies is the handler for fetching all the company details
+func GetAllCompanies(c *gin.Context) {
+	var company service.Company
+
+	allCompanies, err := company.GetAll([]bson.E{})
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error getting all the company details -> %s", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	responseCompanies := make([]service.Company, len(allCompanies))
+	for index, co := range allCompanies {
+		responseCompanies[index] = service.Company{
+			ID:   co.ID,
+			Name: co.Name,
+			Logo: co.Logo,
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": responseCompanies})
+}
+
+// GetCompany is the handler for fetching company details with all the roles it hires for
+func GetCompany(c *gin.Context) {
+	companyID := c.Param("id")
+
+	objectID, err := primitive.ObjectIDFromHex(companyID)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing companyID to object -> %s", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var company service.Company
+
+	err = company.Get([]bson.E{{"_id", objectID}})
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Company not found"})
+			return
+		}
+
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error getting company details for company [%s] -> %s", companyID, err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	var role service.Role
+
+	roles, err := role.GetAll([]bson.E{{"company_ids", objectID}})
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error getting roles for company [%s] -> %s", companyID, err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	company.Roles = make([]service.Role, len(roles))
+	for idx, r := range roles {
+		company.Roles[idx] = service.Role{
+			ID:    r.ID,
+			Name:  r.Name,
+			Image: r.Image,
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": company})
+}
+
+// UpdateCompany is the handler for a moderator to update company details
+func UpdateCompany(c *gin.Context) {
+	_, ok := authorizeModerator(c)
+	if !ok {
+		return
+	}
+
+	companyID := c.Param("id")
+
+	objectID, err := primitive.ObjectIDFromHex(companyID)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing companyID to object -> %s", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var company service.Company
+
+	err = c.ShouldBind(&company)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing the request body -> %s", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	updateFields := bson.D{
+		{"$set", bson.D{
+			{"name", company.Name},
+			{"logo", company.Logo},
+			{"description", company.Description},
+			{"link", company.Link},
+		}},
+	}
+
+	err = company.Update(objectID, updateFields)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Company not found"})
+			return
+		} else if mongo.IsDuplicateKeyError(err) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Company with this name already exists"})
+			return
+		}
+
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error updating company details -> %s", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": "Company updated successfully"})
+}
+
+// DeleteCompany is the handler for a moderator to delete a company and unlink it from the roles
+func DeleteCompany(c *gin.Context) {
+	_, ok := authorizeModerator(c)
+	if !ok {
+		return
+	}
+
+	companyID := c.Param("id")
+
+	objectID, err := primitive.ObjectIDFromHex(companyID)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing companyID to object -> %s", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var company service.Company
+
+	err = company.Delete(objectID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Company not found"})
+			return
+		}
+
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error deleting company -> %s", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": "Company deleted successfully"})
+}
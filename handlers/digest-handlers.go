@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"bytes"
+	"career-compass-go/auth"
+	"career-compass-go/config"
+	"career-compass-go/pkg/logging"
+	"career-compass-go/service"
+	"career-compass-go/utils"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"net/http"
+	"net/url"
+	"runtime"
+)
+
+// GetDigestPreference is the handler to get how often the current user receives the digest of their followed skills
+func GetDigestPreference(c *gin.Context) {
+	user, ok := getCurrentUser(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": service.DigestPreference{Frequency: user.GetDigestFrequency()}})
+}
+
+// UpdateDigestPreference is the handler for the current user to receive the digest daily, weekly or not at all
+func UpdateDigestPreference(c *gin.Context) {
+	var preference service.DigestPreference
+
+	err := c.ShouldBind(&preference)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing the request body -> %s", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userObjectID, err := primitive.ObjectIDFromHex(c.GetString("userID"))
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing userID to object -> %s", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	setDigestFrequency(c, userObjectID, preference.Frequency)
+}
+
+// ConfirmUnsubscribeDigest is the handler for the unsubscribe link of the digest emails, rendering a page that
+// asks to confirm. Link scanners following the link leave the subscription as it is
+func ConfirmUnsubscribeDigest(c *gin.Context) {
+	token := c.Query("token")
+
+	_, ok := verifyUnsubscribeToken(c, token)
+	if !ok {
+		return
+	}
+
+	renderUnsubscribePage(c, gin.H{"Action": fmt.Sprintf("/digest/unsubscribe?token=%s", url.QueryEscape(token))})
+}
+
+// UnsubscribeDigest is the handler for the confirmation page and the one-click unsubscribe of the mail clients
+func UnsubscribeDigest(c *gin.Context) {
+	userID, ok := verifyUnsubscribeToken(c, c.Query("token"))
+	if !ok {
+		return
+	}
+
+	var user service.User
+
+	err := user.Update([]bson.E{{"_id", userID}}, bson.D{{"$set", bson.D{{"digest_frequency", config.DigestNone}}}})
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error unsubscribing user [%s] from the digest -> %s", userID.Hex(), err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	renderUnsubscribePage(c, gin.H{"Unsubscribed": true})
+}
+
+// verifyUnsubscribeToken gets the user of the unsubscribe token, responding with 401 when it is invalid
+func verifyUnsubscribeToken(c *gin.Context, token string) (primitive.ObjectID, bool) {
+	userID, err := auth.VerifyUnsubscribeToken(token)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error verifying unsubscribe token -> %s", err.Error()))
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid unsubscribe link"})
+		return primitive.NilObjectID, false
+	}
+
+	userObjectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing userID to object -> %s", err.Error()))
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid unsubscribe link"})
+		return primitive.NilObjectID, false
+	}
+
+	return userObjectID, true
+}
+
+// renderUnsubscribePage writes the digest unsubscribe page
+func renderUnsubscribePage(c *gin.Context, data gin.H) {
+	var body bytes.Buffer
+
+	err := config.Templates.ExecuteTemplate(&body, "unsubscribeTemplate.html", data)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error executing unsubscribe page template -> %s", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Data(http.StatusOK, "text/html; charset=utf-8", body.Bytes())
+}
+
+// setDigestFrequency stores the user's digest frequency and responds with it
+func setDigestFrequency(c *gin.Context, userID primitive.ObjectID, frequency string) {
+	var user service.User
+
+	err := user.Update([]bson.E{{"_id", userID}}, bson.D{{"$set", bson.D{{"digest_frequency", frequency}}}})
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error updating digest frequency of user [%s] -> %s", userID.Hex(), err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": service.DigestPreference{Frequency: frequency}})
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"career-compass-go/config"
+	"career-compass-go/pkg/events"
+	"career-compass-go/pkg/logging"
+	"career-compass-go/utils"
+	"fmt"
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"io"
+	"net/http"
+	"runtime"
+	"strconv"
+	"time"
+)
+
+// StreamSkillEvents is the handler streaming the new questions, answers and votes of a skill as Server-Sent Events
+func StreamSkillEvents(c *gin.Context) {
+	skillID := c.Param("id")
+
+	_, err := primitive.ObjectIDFromHex(skillID)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing skillID to object -> %s", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	streamEvents(c, events.SkillTopic(skillID))
+}
+
+// StreamUserEvents is the handler streaming the current user's notifications as Server-Sent Events
+func StreamUserEvents(c *gin.Context) {
+	streamEvents(c, events.UserTopic(c.GetString("userID")))
+}
+
+// streamEvents writes the events of the topic to the client until it disconnects,
+// first replaying those it missed after the event in the Last-Event-ID header
+func streamEvents(c *gin.Context, topic string) {
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		// Clients that cannot set headers pass the last event as a query parameter
+		lastEventID = c.Query("lastEventID")
+	}
+
+	var resumeFrom uint64
+	if lastEventID != "" {
+		var err error
+
+		resumeFrom, err = strconv.ParseUint(lastEventID, 10, 64)
+		if err != nil {
+			logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing last event ID [%s] -> %s", lastEventID, err.Error()))
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Last-Event-ID"})
+			return
+		}
+	}
+
+	subscription := events.Default.Subscribe(topic, resumeFrom)
+	defer subscription.Close()
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	heartbeat := time.NewTicker(config.EventHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case event, ok := <-subscription.Events:
+			if !ok {
+				// Dropped for falling behind, the client reconnects with its last event ID
+				return false
+			}
+
+			c.Render(-1, sse.Event{
+				Id:    strconv.FormatUint(event.ID, 10),
+				Event: event.Type,
+				Data:  event.Data,
+			})
+
+			return true
+		case <-heartbeat.C:
+			// Comments keep idle connections open through proxies
+			_, err := io.WriteString(w, ": heartbeat\n\n")
+			return err == nil
+		case <-c.Request.Context().Done():
+			return false
+		}
+	})
+}
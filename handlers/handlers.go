@@ -4,18 +4,24 @@ import (
 	"career-compass-go/auth"
 	"career-compass-go/config"
 	"career-compass-go/mailer"
+	"career-compass-go/pkg/events"
 	"career-compass-go/pkg/logging"
+	"career-compass-go/pkg/mlclient"
+	"career-compass-go/pkg/predictor"
 	"career-compass-go/service"
 	"career-compass-go/utils"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 	"net/http"
 	"runtime"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -71,7 +77,7 @@ func Signup(c *gin.Context) {
 		return
 	}
 
-	// Send OTP via email
+	// Send OTP via email, failures are logged by the mailer
 	go mailer.SendMail(config.MailOTP, user.Email, otp)
 
 	c.JSON(http.StatusOK, gin.H{"data": gin.H{"userID": user.ID}})
@@ -264,10 +270,35 @@ func CreateRole(c *gin.Context) {
 
 // GetAllRoles is the handler for fetching all the role details
 func GetAllRoles(c *gin.Context) {
-	var role service.Role
+	var (
+		role         service.Role
+		salaryFilter service.SalaryFilter
+	)
+
+	err := c.ShouldBindQuery(&salaryFilter)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing salary filters -> %s", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	filters := []bson.E{}
+	if !salaryFilter.IsEmpty() {
+		filters = append(filters, bson.E{"salaries", bson.D{{"$elemMatch", salaryFilter.ElemMatch()}}})
+	}
+
+	findOptions := options.Find()
+	if c.Query("sortBy") == config.SalarySort {
+		// Ascending sorts compare the lowest bound across salaries and descending sorts the highest
+		if c.Query("order") == config.SortDescending {
+			findOptions.SetSort(bson.D{{"salaries.max", -1}})
+		} else {
+			findOptions.SetSort(bson.D{{"salaries.min", 1}})
+		}
+	}
 
 	// Get all role details
-	allRoles, err := role.GetAll([]bson.E{})
+	allRoles, err := role.GetAll(filters, findOptions)
 	if err != nil {
 		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error getting all the role details -> %s", err.Error()))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -277,9 +308,10 @@ func GetAllRoles(c *gin.Context) {
 	responseRoles := make([]service.Role, len(allRoles))
 	for index, r := range allRoles {
 		responseRoles[index] = service.Role{
-			ID:    r.ID,
-			Name:  r.Name,
-			Image: r.Image,
+			ID:       r.ID,
+			Name:     r.Name,
+			Image:    r.Image,
+			Salaries: r.Salaries,
 		}
 	}
 
@@ -298,45 +330,92 @@ func GetRole(c *gin.Context) {
 		return
 	}
 
-	// Get the role details
-	filter := []bson.E{
-		{"_id", objectID},
-	}
-
+	// Get the role details with its skills and companies
 	var role service.Role
 
-	err = role.Get(filter)
+	err = role.GetDetails(objectID)
 	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Role not found"})
+			return
+		}
+
 		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error getting role details for role [%s] -> %s", roleID, err.Error()))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	role.Skills = make([]service.Skill, len(role.SkillIDs))
-	for idx, skillID := range role.SkillIDs {
-		var skill service.Skill
+	role.SkillIDs = nil
+	role.CompanyIDs = nil
 
-		filter = []bson.E{
-			{"_id", skillID},
-		}
+	bookmarked, ok := getBookmarked(c, role.ID)
+	if !ok {
+		return
+	}
+
+	role.Bookmarked = bookmarked[role.ID]
+
+	c.JSON(http.StatusOK, gin.H{"data": role})
+}
 
-		err = skill.Get(filter)
+// CompareRoles is the handler for comparing the skills, duties, salaries and companies of the selected roles
+func CompareRoles(c *gin.Context) {
+	ids := strings.Split(c.Query("ids"), ",")
+
+	roleIDs := make([]primitive.ObjectID, 0, len(ids))
+	seen := make(map[primitive.ObjectID]bool)
+
+	for _, id := range ids {
+		objectID, err := primitive.ObjectIDFromHex(strings.TrimSpace(id))
 		if err != nil {
-			logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error getting skill details for skill [%s] -> %s", skillID.Hex(), err.Error()))
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing roleID [%s] to object -> %s", id, err.Error()))
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid roleID %s", id)})
 			return
 		}
 
-		role.Skills[idx] = service.Skill{
-			ID:    skillID,
-			Name:  skill.Name,
-			Image: skill.Image,
+		if !seen[objectID] {
+			seen[objectID] = true
+			roleIDs = append(roleIDs, objectID)
 		}
 	}
 
-	role.SkillIDs = nil
+	if len(roleIDs) < config.MinCompareRoles || len(roleIDs) > config.MaxCompareRoles {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Invalid number of roles to compare -> %d", len(roleIDs)))
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Select between %d and %d distinct roles to compare", config.MinCompareRoles, config.MaxCompareRoles)})
+		return
+	}
 
-	c.JSON(http.StatusOK, gin.H{"data": role})
+	var (
+		role         service.Role
+		salaryFilter service.SalaryFilter
+	)
+
+	err := c.ShouldBindQuery(&salaryFilter)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing salary filters -> %s", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	comparison, err := role.Compare(roleIDs, salaryFilter)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Roles not found"})
+			return
+		}
+
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error comparing roles -> %s", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if len(comparison.Roles) != len(roleIDs) {
+		logging.Logger.Info(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Found %d of %d roles to compare", len(comparison.Roles), len(roleIDs)))
+		c.JSON(http.StatusNotFound, gin.H{"error": "One or more roles not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": comparison})
 }
 
 // CreateSkill is the handler for creating new skill entry
@@ -350,6 +429,12 @@ func CreateSkill(c *gin.Context) {
 		return
 	}
 
+	// The rating dimension lets the learning plans count the skill as known from the assessment ratings
+	if skill.Dimension != "" && !slices.Contains(service.RatingDimensions, skill.Dimension) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Unknown rating dimension [%s]", skill.Dimension)})
+		return
+	}
+
 	err = skill.Create()
 	if err != nil {
 		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error creating skill document -> %s", err.Error()))
@@ -396,43 +481,29 @@ func GetSkill(c *gin.Context) {
 		return
 	}
 
-	// Get the skill details
-	filter := []bson.E{
-		{"_id", objectID},
-	}
-
+	// Get the skill details with its roles and learning resources ranked by usefulness
 	var skill service.Skill
 
-	err = skill.Get(filter)
+	err = skill.GetDetails(objectID)
 	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Skill not found"})
+			return
+		}
+
 		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error getting skill details for skill [%s] -> %s", skillID, err.Error()))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	skill.Roles = make([]service.Role, len(skill.RoleIDs))
-	for idx, roleID := range skill.RoleIDs {
-		var role service.Role
-
-		filter = []bson.E{
-			{"_id", roleID},
-		}
-
-		err = role.Get(filter)
-		if err != nil {
-			logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error getting role details for role [%s] -> %s", roleID.Hex(), err.Error()))
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
-		}
+	skill.RoleIDs = nil
 
-		skill.Roles[idx] = service.Role{
-			ID:    roleID,
-			Name:  role.Name,
-			Image: role.Image,
-		}
+	bookmarked, ok := getBookmarked(c, skill.ID)
+	if !ok {
+		return
 	}
 
-	skill.RoleIDs = nil
+	skill.Bookmarked = bookmarked[skill.ID]
 
 	c.JSON(http.StatusOK, gin.H{"data": skill})
 }
@@ -519,6 +590,9 @@ func AddQuestion(c *gin.Context) {
 
 	question.Status = config.QuestionUnresolved
 	question.Upvote = 0
+	question.UpvoteBy = []primitive.ObjectID{}
+	question.Tags = utils.NormalizeTags(question.Tags)
+	question.AnswerCount = 0
 	question.CreatedAt = currTime
 	question.UpdatedAt = currTime
 
@@ -544,6 +618,32 @@ func AddQuestion(c *gin.Context) {
 
 	question.UserName = user.Username
 
+	// Point the asker to similar questions of the skill unless they choose to post anyway
+	force, _ := strconv.ParseBool(c.Query("force"))
+	if !force {
+		duplicates, err := question.FindDuplicates()
+		if err != nil {
+			logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error finding duplicates of the question -> %s", err.Error()))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		if len(duplicates) > 0 {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":      "Similar questions have already been asked for this skill, retry with force=true to post anyway",
+				"duplicates": duplicates,
+			})
+			return
+		}
+	}
+
+	question.ContentHTML, question.Mentions, question.Unresolved, err = renderContent(question.Content)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error rendering question content -> %s", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
 	err = question.Add()
 	if err != nil {
 		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error creating question details -> %s", err.Error()))
@@ -551,7 +651,24 @@ func AddQuestion(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"data": gin.H{"questionID": question.ID}})
+	// Authors follow their own questions to hear about answers
+	subscribe(question.UserID, question.ID, config.SubscriptionQuestion)
+
+	notification := service.Notification{
+		Type:       config.NotificationQuestion,
+		ActorID:    question.UserID,
+		ActorName:  question.UserName,
+		SkillID:    question.SkillID,
+		QuestionID: question.ID,
+		Message:    fmt.Sprintf("%s asked \"%s\"", question.UserName, question.Title),
+	}
+
+	go notify(notification, []primitive.ObjectID{question.SkillID})
+	go notifyMentions(question.UserID, question.UserName, question.ID, question.ID, question.Mentions, nil)
+
+	events.Default.Publish(events.SkillTopic(question.SkillID.Hex()), config.EventQuestion, question)
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"questionID": question.ID, "unresolvedMentions": question.Unresolved}})
 }
 
 // GetQuestions is the handler for fetching questions with answers for a skill
@@ -568,39 +685,167 @@ func GetQuestions(c *gin.Context) {
 
 	filters := []bson.E{
 		{"skill_id", skillIDObject},
+		{"hidden", bson.D{{"$ne", true}}},
 	}
 
+	var sort bson.D
+	switch c.Query("sortBy") {
+	case config.VotesSort:
+		sort = bson.D{{"upvote", -1}, {"created_at", -1}}
+	case config.NewestSort:
+		sort = bson.D{{"created_at", -1}}
+	}
+
+	// Get the questions with their answers, accepted answer first followed by the highest scored ones
 	var question service.Question
-	resp, err = question.GetAll(filters)
+	resp, err = question.GetAllWithAnswers(filters, sort)
 	if err != nil {
 		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error getting question documents for skill [%s] -> %s", skillID, err.Error()))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
+	// Decorate the questions and answers with their comment counts and previews
+	postIDs := make([]primitive.ObjectID, 0, len(resp))
+	for _, ques := range resp {
+		postIDs = append(postIDs, ques.ID)
+		for _, ans := range ques.Answers {
+			postIDs = append(postIDs, ans.ID)
+		}
+	}
+
+	var comment service.Comment
+
+	summaries, err := comment.GetSummaries(postIDs)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error getting comment summaries for skill [%s] -> %s", skillID, err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	questionIDs := make([]primitive.ObjectID, len(resp))
 	for idx, ques := range resp {
-		filters = []bson.E{
-			{"question_id", ques.ID},
+		questionIDs[idx] = ques.ID
+	}
+
+	bookmarked, ok := getBookmarked(c, questionIDs...)
+	if !ok {
+		return
+	}
+
+	for idx := range resp {
+		resp[idx].Bookmarked = bookmarked[resp[idx].ID]
+		resp[idx].CommentCount = summaries[resp[idx].ID].Count
+		resp[idx].Comments = summaries[resp[idx].ID].Preview
+
+		for ansIdx := range resp[idx].Answers {
+			answer := &resp[idx].Answers[ansIdx]
+			answer.CommentCount = summaries[answer.ID].Count
+			answer.Comments = summaries[answer.ID].Preview
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": resp})
+}
+
+// GetQuestionFeed is the handler for browsing questions across skills by tags, status, author, skill and date
+func GetQuestionFeed(c *gin.Context) {
+	var query service.QuestionFeedQuery
+
+	err := c.ShouldBindQuery(&query)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing feed query -> %s", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	filters := []bson.E{
+		{"hidden", bson.D{{"$ne", true}}},
+	}
+
+	if tags := utils.NormalizeTags(strings.Split(query.Tags, ",")); len(tags) > 0 {
+		filters = append(filters, bson.E{"tags", bson.D{{"$all", tags}}})
+	}
+
+	if query.Status != "" {
+		filters = append(filters, bson.E{"status", query.Status})
+	}
+
+	for field, id := range map[string]string{"user_id": query.AuthorID, "skill_id": query.SkillID} {
+		if id == "" {
+			continue
 		}
 
-		var ans service.Answer
-		resp[idx].Answers, err = ans.GetAll(filters)
+		objectID, err := primitive.ObjectIDFromHex(id)
 		if err != nil {
-			logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error getting answer documents for question [%s] -> %s", ques.ID.Hex(), err.Error()))
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing %s [%s] to object -> %s", field, id, err.Error()))
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid ID %s", id)})
 			return
 		}
+
+		filters = append(filters, bson.E{field, objectID})
 	}
 
-	c.JSON(http.StatusOK, gin.H{"data": resp})
+	dateRange := bson.D{}
+	if !query.From.IsZero() {
+		dateRange = append(dateRange, bson.E{"$gte", query.From})
+	}
+	if !query.To.IsZero() {
+		// The end date is inclusive of the whole day
+		dateRange = append(dateRange, bson.E{"$lt", query.To.AddDate(0, 0, 1)})
+	}
+	if len(dateRange) > 0 {
+		filters = append(filters, bson.E{"created_at", dateRange})
+	}
+
+	findOptions := options.Find().SetSkip((query.Page - 1) * query.Limit).SetLimit(query.Limit)
+	switch query.SortBy {
+	case config.VotesSort:
+		findOptions.SetSort(bson.D{{"upvote", -1}, {"created_at", -1}})
+	case config.UnansweredSort:
+		findOptions.SetSort(bson.D{{"answer_count", 1}, {"created_at", -1}})
+	default:
+		findOptions.SetSort(bson.D{{"created_at", -1}})
+	}
+
+	var question service.Question
+
+	questions, err := question.GetAll(filters, findOptions)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error getting question feed -> %s", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	total, err := question.Count(filters)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error counting question feed -> %s", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"questions": questions, "page": query.Page, "limit": query.Limit, "total": total}})
+}
+
+// GetTags is the handler for autocompleting question tags by prefix
+func GetTags(c *gin.Context) {
+	prefix := strings.ToLower(strings.TrimSpace(c.Query("prefix")))
+
+	var question service.Question
+
+	tags, err := question.GetTags(prefix, config.TagSuggestionLimit)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error getting tags for prefix [%s] -> %s", prefix, err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": tags})
 }
 
 // UpdateQuestion is the handler to update question
 func UpdateQuestion(c *gin.Context) {
-	var (
-		questionID = c.Param("id")
-		status     = c.Query("status")
-	)
+	status := c.Query("status")
 
 	if status != config.QuestionUnresolved && status != config.QuestionResolved {
 		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Invalid status input recieved -> %s", status))
@@ -608,30 +853,121 @@ func UpdateQuestion(c *gin.Context) {
 		return
 	}
 
-	questionObjectID, err := primitive.ObjectIDFromHex(questionID)
-	if err != nil {
-		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing questionID to object -> %s", err.Error()))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	question, ok := getQuestion(c)
+	if !ok {
+		return
+	}
+
+	user, ok := authorizeAuthor(c, question.UserID)
+	if !ok {
 		return
 	}
 
 	updateFields := bson.D{
 		{"$set", bson.D{
 			{"status", status},
+			{"updated_at", time.Now()},
 		}},
 	}
 
-	var question service.Question
-	err = question.Update(questionObjectID, updateFields)
+	err := question.Update(question.ID, updateFields)
 	if err != nil {
 		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error updating question details -> %s", err.Error()))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
+	if question.Status != status {
+		go notifyStatusChange(question, status, user.ID, user.Username)
+	}
+
 	c.JSON(http.StatusOK, gin.H{"data": "Question updated successfully"})
 }
 
+// UpvoteQuestion is the handler for the user to upvote a question
+func UpvoteQuestion(c *gin.Context) {
+	setQuestionUpvote(c, true)
+}
+
+// RemoveQuestionUpvote is the handler for the user to withdraw their upvote of a question
+func RemoveQuestionUpvote(c *gin.Context) {
+	setQuestionUpvote(c, false)
+}
+
+// setQuestionUpvote adds or removes the user's upvote of the question in the request path and responds with the vote count
+func setQuestionUpvote(c *gin.Context, upvote bool) {
+	questionID := c.Param("id")
+
+	questionObjectID, err := primitive.ObjectIDFromHex(questionID)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing questionID to object -> %s", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userObjectID, err := primitive.ObjectIDFromHex(c.GetString("userID"))
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing userID to object -> %s", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	var question service.Question
+
+	err = question.Get([]bson.E{{"_id", questionObjectID}})
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Question not found"})
+			return
+		}
+
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error getting question [%s] -> %s", questionID, err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Votes earn the author reputation, so authors cannot vote on their own posts
+	if question.UserID == userObjectID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You cannot vote on your own question"})
+		return
+	}
+
+	var changed bool
+
+	if upvote {
+		changed, err = question.AddUpvote(questionObjectID, userObjectID)
+	} else {
+		changed, err = question.RemoveUpvote(questionObjectID, userObjectID)
+	}
+
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error updating upvote of question [%s] -> %s", questionID, err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Repeated votes leave the question untouched, so respond with its current state either way
+	err = question.Get([]bson.E{{"_id", questionObjectID}})
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Question not found"})
+			return
+		}
+
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error getting question [%s] -> %s", questionID, err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if changed {
+		go recalculateReputation(question.UserID)
+
+		events.Default.Publish(events.SkillTopic(question.SkillID.Hex()), config.EventQuestionVote, gin.H{"questionID": question.ID, "upvote": question.Upvote})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"questionID": question.ID, "upvote": question.Upvote, "upvoted": upvote}})
+}
+
 // AddAnswer is the handler to add new answer to a question
 func AddAnswer(c *gin.Context) {
 	var answer service.Answer
@@ -647,6 +983,10 @@ func AddAnswer(c *gin.Context) {
 
 	answer.CreatedAt = currTime
 	answer.UpdatedAt = currTime
+	answer.UpvoteBy = []primitive.ObjectID{}
+	answer.DownvoteBy = []primitive.ObjectID{}
+	answer.Score = 0
+	answer.Accepted = false
 
 	answer.UserID, err = primitive.ObjectIDFromHex(c.GetString("userID"))
 	if err != nil {
@@ -670,6 +1010,27 @@ func AddAnswer(c *gin.Context) {
 
 	answer.UserName = user.Username
 
+	var question service.Question
+
+	err = question.Get([]bson.E{{"_id", answer.QuestionID}})
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Question not found"})
+			return
+		}
+
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error getting question [%s] -> %s", answer.QuestionID.Hex(), err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	answer.ContentHTML, answer.Mentions, answer.Unresolved, err = renderContent(answer.Content)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error rendering answer content -> %s", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
 	err = answer.Add()
 	if err != nil {
 		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error creating answer details -> %s", err.Error()))
@@ -677,47 +1038,314 @@ func AddAnswer(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"data": gin.H{"answerID": answer.ID}})
+	err = question.Update(answer.QuestionID, bson.D{{"$inc", bson.D{{"answer_count", 1}}}})
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error updating answer count of question [%s] -> %s", answer.QuestionID.Hex(), err.Error()))
+	}
+
+	notification := service.Notification{
+		Type:       config.NotificationAnswer,
+		ActorID:    answer.UserID,
+		ActorName:  answer.UserName,
+		SkillID:    question.SkillID,
+		QuestionID: question.ID,
+		PostID:     answer.ID,
+		Message:    fmt.Sprintf("%s answered \"%s\"", answer.UserName, question.Title),
+	}
+
+	go notify(notification, []primitive.ObjectID{question.ID})
+	go notifyMentions(answer.UserID, answer.UserName, question.ID, answer.ID, answer.Mentions, nil)
+
+	events.Default.Publish(events.SkillTopic(question.SkillID.Hex()), config.EventAnswer, answer)
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"answerID": answer.ID, "unresolvedMentions": answer.Unresolved}})
+}
+
+// UpvoteAnswer is the handler for the user to upvote an answer
+func UpvoteAnswer(c *gin.Context) {
+	setAnswerVote(c, config.VoteUp)
+}
+
+// DownvoteAnswer is the handler for the user to downvote an answer
+func DownvoteAnswer(c *gin.Context) {
+	setAnswerVote(c, config.VoteDown)
+}
+
+// RemoveAnswerVote is the handler for the user to withdraw their vote on an answer
+func RemoveAnswerVote(c *gin.Context) {
+	setAnswerVote(c, config.VoteNone)
+}
+
+// setAnswerVote sets the user's vote on the answer in the request path and responds with the answer score
+func setAnswerVote(c *gin.Context, vote int) {
+	answerID := c.Param("id")
+
+	answerObjectID, err := primitive.ObjectIDFromHex(answerID)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing answerID to object -> %s", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userObjectID, err := primitive.ObjectIDFromHex(c.GetString("userID"))
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing userID to object -> %s", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	var answer service.Answer
+
+	err = answer.Get([]bson.E{{"_id", answerObjectID}})
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Answer not found"})
+			return
+		}
+
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error getting answer [%s] -> %s", answerID, err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Votes earn the author reputation, so authors cannot vote on their own posts
+	if answer.UserID == userObjectID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You cannot vote on your own answer"})
+		return
+	}
+
+	changed, err := answer.Vote(answerObjectID, userObjectID, vote)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Answer not found"})
+			return
+		}
+
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error voting on answer [%s] -> %s", answerID, err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	err = answer.Get([]bson.E{{"_id", answerObjectID}})
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error getting answer [%s] -> %s", answerID, err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Repeating the same vote leaves the reputation as it was
+	if changed {
+		go recalculateReputation(answer.UserID)
+	}
+
+	var question service.Question
+
+	err = question.Get([]bson.E{{"_id", answer.QuestionID}})
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error getting question [%s] of the voted answer -> %s", answer.QuestionID.Hex(), err.Error()))
+	} else {
+		events.Default.Publish(events.SkillTopic(question.SkillID.Hex()), config.EventAnswerVote, gin.H{"answerID": answer.ID, "questionID": answer.QuestionID, "score": answer.Score})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"answerID": answer.ID, "score": answer.Score, "vote": vote}})
+}
+
+// AcceptAnswer is the handler for the question author to accept an answer and resolve the question
+func AcceptAnswer(c *gin.Context) {
+	setAnswerAccepted(c, true)
+}
+
+// UnacceptAnswer is the handler for the question author to withdraw the acceptance of an answer
+func UnacceptAnswer(c *gin.Context) {
+	setAnswerAccepted(c, false)
+}
+
+// setAnswerAccepted updates the accepted state of the answer in the request path along with its question status
+func setAnswerAccepted(c *gin.Context, accepted bool) {
+	answerID := c.Param("id")
+
+	answerObjectID, err := primitive.ObjectIDFromHex(answerID)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing answerID to object -> %s", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var (
+		answer   service.Answer
+		question service.Question
+	)
+
+	err = answer.Get([]bson.E{{"_id", answerObjectID}})
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Answer not found"})
+			return
+		}
+
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error getting answer [%s] -> %s", answerID, err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	err = question.Get([]bson.E{{"_id", answer.QuestionID}})
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Question not found"})
+			return
+		}
+
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error getting question [%s] -> %s", answer.QuestionID.Hex(), err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if question.UserID.Hex() != c.GetString("userID") {
+		logging.Logger.Info(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("User [%s] is not the author of question [%s]", c.GetString("userID"), question.ID.Hex()))
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the question author can accept an answer"})
+		return
+	}
+
+	// Withdrawing an answer that is not accepted must not reopen a question resolved by another answer
+	if !accepted && !answer.Accepted {
+		c.JSON(http.StatusOK, gin.H{"data": gin.H{"answerID": answerObjectID, "accepted": false, "questionStatus": question.Status}})
+		return
+	}
+
+	// Answers losing their acceptance lose the reputation it earned their authors
+	acceptedAnswers, err := answer.GetAll([]bson.E{{"question_id", question.ID}, {"accepted", true}})
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error getting accepted answers of question [%s] -> %s", question.ID.Hex(), err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	status := config.QuestionUnresolved
+	if accepted {
+		status = config.QuestionResolved
+	}
+
+	// Authors may accept their own answer to resolve the question, the recalculation gives it no reputation
+	err = answer.SetAccepted(answerObjectID, question.ID, accepted, status)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error updating accepted state of answer [%s] -> %s", answerID, err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	authorIDs := []primitive.ObjectID{answer.UserID}
+	for _, ans := range acceptedAnswers {
+		authorIDs = append(authorIDs, ans.UserID)
+	}
+
+	go recalculateReputation(authorIDs...)
+
+	if accepted {
+		notification := service.Notification{
+			Type:       config.NotificationAccepted,
+			ActorID:    question.UserID,
+			ActorName:  question.UserName,
+			SkillID:    question.SkillID,
+			QuestionID: question.ID,
+			PostID:     answer.ID,
+			Message:    fmt.Sprintf("%s accepted your answer to \"%s\"", question.UserName, question.Title),
+		}
+
+		go notify(notification, nil, answer.UserID)
+	}
+
+	if question.Status != status {
+		go notifyStatusChange(&question, status, question.UserID, question.UserName)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"answerID": answerObjectID, "accepted": accepted, "questionStatus": status}})
+}
+
+// predictQuery holds the number of ranked candidate roles to predict
+type predictQuery struct {
+	Top int `form:"top,default=3" binding:"min=1,max=10"`
 }
 
-// Predict is the handler to determine the user's suitable role based on their assessment ratings
+// Predict is the handler to determine the user's suitable roles based on their assessment ratings
 func Predict(c *gin.Context) {
 	var (
 		ratingsData service.RatingsData
-		predictResp map[string]any
+		query       predictQuery
 	)
 
-	userID := c.Query("userID")
+	userID := c.GetString("userID")
 
-	err := c.ShouldBind(&ratingsData)
+	err := c.ShouldBindQuery(&query)
 	if err != nil {
-		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing request body -> %s", err.Error()))
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing predict query -> %s", err.Error()))
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	res, err := utils.RestPost(fmt.Sprintf("%s/predict", config.MLServerURL), ratingsData)
+	err = c.ShouldBind(&ratingsData)
 	if err != nil {
-		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error making predict api call to ML server -> %s", err.Error()))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing request body -> %s", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	defer res.Body.Close()
 
-	err = json.NewDecoder(res.Body).Decode(&predictResp)
+	userObjectID, err := primitive.ObjectIDFromHex(userID)
 	if err != nil {
-		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing response body -> %s", err.Error()))
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing userID to object -> %s", err.Error()))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	if res.StatusCode != http.StatusOK {
-		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error from ML engine -> %v", predictResp))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	prediction, err := predictor.Default.Predict(c.Request.Context(), ratingsData, query.Top)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error predicting role for user {%s} -> %s", userID, err.Error()))
+		c.JSON(predictionErrorStatus(err), gin.H{"error": err.Error()})
 		return
+	}
+
+	logging.Logger.Info(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Response from %s predictor for user {%s} -> %s", prediction.Predictor, userID, prediction.Role))
+
+	resp := gin.H{
+		"prediction":   prediction.Role,
+		"modelVersion": prediction.ModelVersion,
+		"predictor":    prediction.Predictor,
+		"candidates":   prediction.Candidates,
+	}
+
+	// Keep the assessment in the user's history, still answering with the prediction if that fails
+	assessment, err := saveAssessment(userObjectID, ratingsData, prediction.Role, prediction.ModelVersion)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error saving assessment of user [%s] -> %s", userID, err.Error()))
 	} else {
-		logging.Logger.Info(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Response from predict for user {%s} -> %s", userID, predictResp["prediction"].(string)))
-		c.JSON(http.StatusOK, gin.H{"data": predictResp})
-		return
+		resp["assessmentID"] = assessment.ID
+	}
+
+	// Lead the user from the predicted role to the skills and resources they still need for it
+	var plan service.LearningPlan
+
+	err = plan.Build(prediction.Role, userObjectID, ratingsData)
+	switch {
+	case err == nil:
+		resp["learningPlan"] = plan
+	case errors.Is(err, mongo.ErrNoDocuments):
+		logging.Logger.Warning(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("No catalog role matches the predicted role [%s]", prediction.Role))
+	default:
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error building learning plan for role [%s] -> %s", prediction.Role, err.Error()))
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": resp})
+}
+
+// predictionErrorStatus maps a prediction failure to the response status, telling ML server outages from timeouts
+func predictionErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, mlclient.ErrCircuitOpen):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, mlclient.ErrTimeout):
+		return http.StatusGatewayTimeout
+	case errors.Is(err, mlclient.ErrBadGateway):
+		return http.StatusBadGateway
+	default:
+		return http.StatusInternalServerError
 	}
 }
@@ -0,0 +1,154 @@
+package handlers_test
+
+import (
+	"career-compass-go/config"
+	"career-compass-go/handlers"
+	"career-compass-go/pkg/logging"
+	"career-compass-go/pkg/setting"
+	"career-compass-go/service"
+	"context"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+// testDB is the scratch database the tests seed, nil when mongo is unreachable
+var testDB *mongo.Database
+
+func TestMain(m *testing.M) {
+	logging.Setup()
+	gin.SetMode(gin.TestMode)
+
+	client, err := setting.ConnectToMongo(config.ViperConfig.GetString("MONGO_URI"))
+	if err == nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		err = client.Ping(ctx, nil)
+		cancel()
+	}
+
+	if err == nil {
+		testDB = client.Database(fmt.Sprintf("%s_test_%d", config.MongoDBName, time.Now().UnixNano()))
+		config.QuestionCollection = testDB.Collection("questions")
+		config.AnswerCollection = testDB.Collection("answers")
+	}
+
+	code := m.Run()
+
+	// Drop the scratch database whatever the outcome of the run
+	if testDB != nil {
+		_ = testDB.Drop(context.TODO())
+		setting.CloseMongoClient(client)
+	}
+
+	os.Exit(code)
+}
+
+// seedPosts inserts a question and an answer written by the author
+func seedPosts(t *testing.T, authorID primitive.ObjectID) (service.Question, service.Answer) {
+	t.Helper()
+
+	if testDB == nil {
+		t.Skip("mongo is unreachable at MONGO_URI")
+	}
+
+	currTime := time.Now()
+
+	question := service.Question{
+		ID:        primitive.NewObjectID(),
+		UserID:    authorID,
+		SkillID:   primitive.NewObjectID(),
+		Title:     "How do I get started?",
+		Content:   "Where should a beginner start?",
+		Status:    config.QuestionUnresolved,
+		CreatedAt: currTime,
+		UpdatedAt: currTime,
+	}
+
+	answer := service.Answer{
+		ID:         primitive.NewObjectID(),
+		QuestionID: question.ID,
+		UserID:     authorID,
+		Content:    "Start with the basics.",
+		CreatedAt:  currTime,
+		UpdatedAt:  currTime,
+	}
+
+	_, err := config.QuestionCollection.InsertOne(context.TODO(), question)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = config.AnswerCollection.InsertOne(context.TODO(), answer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return question, answer
+}
+
+// serve calls the handler for the post in the request path as the given user
+func serve(handler gin.HandlerFunc, postID, userID primitive.ObjectID) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPut, "/", nil)
+	c.Params = gin.Params{{Key: "id", Value: postID.Hex()}}
+	c.Set("userID", userID.Hex())
+
+	handler(c)
+
+	return w
+}
+
+func TestUpvoteOwnQuestion(t *testing.T) {
+	authorID := primitive.NewObjectID()
+	question, _ := seedPosts(t, authorID)
+
+	w := serve(handlers.UpvoteQuestion, question.ID, authorID)
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusForbidden)
+	}
+
+	var stored service.Question
+
+	err := stored.Get([]bson.E{{"_id", question.ID}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if stored.Upvote != 0 || len(stored.UpvoteBy) != 0 {
+		t.Errorf("upvote = %d by %v, want no upvote", stored.Upvote, stored.UpvoteBy)
+	}
+}
+
+func TestVoteOwnAnswer(t *testing.T) {
+	authorID := primitive.NewObjectID()
+	_, answer := seedPosts(t, authorID)
+
+	for name, handler := range map[string]gin.HandlerFunc{"up": handlers.UpvoteAnswer, "down": handlers.DownvoteAnswer} {
+		t.Run(name, func(t *testing.T) {
+			w := serve(handler, answer.ID, authorID)
+			if w.Code != http.StatusForbidden {
+				t.Fatalf("status = %d, want %d", w.Code, http.StatusForbidden)
+			}
+		})
+	}
+
+	var stored service.Answer
+
+	err := stored.Get([]bson.E{{"_id", answer.ID}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if stored.Score != 0 {
+		t.Errorf("score = %d, want 0", stored.Score)
+	}
+}
@@ -0,0 +1,482 @@
+package handlers
+
+import (
+	"career-compass-go/config"
+	"career-compass-go/pkg/logging"
+	"career-compass-go/service"
+	"career-compass-go/utils"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"net/http"
+	"runtime"
+	"time"
+)
+
+// moderationReview holds a moderator's decision on a flagged post
+type moderationReview struct {
+	PostType string `json:"postType" binding:"required,oneof=question answer comment"`
+	Action   string `json:"action" binding:"required,oneof=hide delete dismiss warn"`
+	Reason   string `json:"reason" binding:"max=500"`
+}
+
+// questionMerge holds the duplicates to merge into a question
+type questionMerge struct {
+	DuplicateIDs []primitive.ObjectID `json:"duplicateIDs" binding:"required,min=1,max=20,unique"`
+}
+
+// suspension holds the expiry and reason of a user suspension
+type suspension struct {
+	Until  time.Time `json:"until" binding:"required"`
+	Reason string    `json:"reason" binding:"required,max=500"`
+}
+
+// FlagPost is the handler for the user to flag a question, answer or comment for moderation
+func FlagPost(c *gin.Context) {
+	var flag service.Flag
+
+	err := c.ShouldBind(&flag)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing the request body -> %s", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	flag.PostID, err = primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing postID to object -> %s", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	flag.UserID, err = primitive.ObjectIDFromHex(c.GetString("userID"))
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing userID to object -> %s", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	authorID, err := service.GetPostAuthor(flag.PostType, flag.PostID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("The flagged %s was not found", flag.PostType)})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	flag.Status = config.FlagPending
+	flag.CreatedAt = time.Now()
+
+	err = flag.Add()
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("You have already flagged this %s", flag.PostType)})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Posts collecting enough flags are hidden until a moderator reviews them
+	count, err := flag.CountPending(flag.PostID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Concurrent flags may all pass the threshold, only the one actually hiding the post records it
+	hidden := false
+	if count >= config.FlagHideThreshold {
+		hid, err := service.HidePost(flag.PostType, flag.PostID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		hidden = true
+		if !hid {
+			c.JSON(http.StatusOK, gin.H{"data": gin.H{"flagID": flag.ID, "hidden": hidden}})
+			return
+		}
+
+		action := service.ModerationAction{
+			Action:       config.ModerationAutoHide,
+			PostID:       flag.PostID,
+			PostType:     flag.PostType,
+			TargetUserID: authorID,
+			Reason:       fmt.Sprintf("Flagged by %d users", count),
+		}
+
+		err = action.Add()
+		if err != nil {
+			logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error recording auto-hide of %s [%s] -> %s", flag.PostType, flag.PostID.Hex(), err.Error()))
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"flagID": flag.ID, "hidden": hidden}})
+}
+
+// GetModerationQueue is the handler for the moderator to get the flagged posts awaiting review
+func GetModerationQueue(c *gin.Context) {
+	_, ok := authorizeModerator(c)
+	if !ok {
+		return
+	}
+
+	var flag service.Flag
+
+	queue, err := flag.GetQueue()
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error getting moderation queue -> %s", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": queue})
+}
+
+// ReviewPost is the handler for the moderator to hide, delete, dismiss or warn about a flagged post
+func ReviewPost(c *gin.Context) {
+	var review moderationReview
+
+	err := c.ShouldBind(&review)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing the request body -> %s", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	postID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing postID to object -> %s", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	moderator, ok := authorizeModerator(c)
+	if !ok {
+		return
+	}
+
+	authorID, err := service.GetPostAuthor(review.PostType, postID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("The reviewed %s was not found", review.PostType)})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	switch review.Action {
+	case config.ModerationHide:
+		err = service.SetPostHidden(review.PostType, postID, true)
+	case config.ModerationDismiss:
+		// Dismissing the flags restores a post hidden automatically
+		err = service.SetPostHidden(review.PostType, postID, false)
+	case config.ModerationDelete:
+		err = removePost(review.PostType, postID)
+	case config.ModerationWarn:
+		var user service.User
+		err = user.Update([]bson.E{{"_id", authorID}}, bson.D{{"$inc", bson.D{{"warnings", 1}}}})
+	}
+
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error applying [%s] to %s [%s] -> %s", review.Action, review.PostType, postID.Hex(), err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	var flag service.Flag
+
+	err = flag.Resolve(postID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	action := service.ModerationAction{
+		Action:        review.Action,
+		PostID:        postID,
+		PostType:      review.PostType,
+		TargetUserID:  authorID,
+		ModeratorID:   moderator.ID,
+		ModeratorName: moderator.Username,
+		Reason:        review.Reason,
+	}
+
+	err = action.Add()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"actionID": action.ID, "action": action.Action}})
+}
+
+// removePost deletes a question, answer or comment along with everything that depends on it
+func removePost(postType string, postID primitive.ObjectID) error {
+	filters := []bson.E{{"_id", postID}}
+
+	switch postType {
+	case config.QuestionPost:
+		var question service.Question
+
+		err := question.Get(filters)
+		if err != nil {
+			return err
+		}
+
+		return removeQuestion(&question)
+	case config.AnswerPost:
+		var answer service.Answer
+
+		err := answer.Get(filters)
+		if err != nil {
+			return err
+		}
+
+		return removeAnswer(&answer)
+	default:
+		var comment service.Comment
+
+		return comment.Delete(postID)
+	}
+}
+
+// SuspendUser is the handler for the moderator to suspend a user until the given time
+func SuspendUser(c *gin.Context) {
+	var req suspension
+
+	err := c.ShouldBind(&req)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing the request body -> %s", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !req.Until.After(time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "The suspension must end in the future"})
+		return
+	}
+
+	updateFields := bson.D{
+		{"$set", bson.D{
+			{"suspended_until", req.Until},
+			{"suspension_reason", req.Reason},
+		}},
+	}
+
+	setSuspension(c, updateFields, config.ModerationSuspend, req.Reason)
+}
+
+// LiftSuspension is the handler for the moderator to end the suspension of a user
+func LiftSuspension(c *gin.Context) {
+	updateFields := bson.D{
+		{"$unset", bson.D{
+			{"suspended_until", ""},
+			{"suspension_reason", ""},
+		}},
+	}
+
+	setSuspension(c, updateFields, config.ModerationUnsuspend, "")
+}
+
+// setSuspension applies the suspension update to the user in the request path and records it in the audit trail
+func setSuspension(c *gin.Context, updateFields bson.D, actionName, reason string) {
+	userID := c.Param("id")
+
+	userObjectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing userID to object -> %s", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	moderator, ok := authorizeModerator(c)
+	if !ok {
+		return
+	}
+
+	var user service.User
+
+	err = user.Get([]bson.E{{"_id", userObjectID}})
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	err = user.Update([]bson.E{{"_id", userObjectID}}, updateFields)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error updating suspension of user [%s] -> %s", userID, err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	action := service.ModerationAction{
+		Action:        actionName,
+		TargetUserID:  userObjectID,
+		ModeratorID:   moderator.ID,
+		ModeratorName: moderator.Username,
+		Reason:        reason,
+	}
+
+	err = action.Add()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"actionID": action.ID, "action": action.Action}})
+}
+
+// GetModerationActions is the handler for the moderator to browse the audit trail, optionally by user or post
+func GetModerationActions(c *gin.Context) {
+	_, ok := authorizeModerator(c)
+	if !ok {
+		return
+	}
+
+	filters := []bson.E{}
+
+	for param, field := range map[string]string{"userID": "target_user_id", "postID": "post_id", "moderatorID": "moderator_id"} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+
+		objectID, err := primitive.ObjectIDFromHex(value)
+		if err != nil {
+			logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing %s to object -> %s", param, err.Error()))
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		filters = append(filters, bson.E{field, objectID})
+	}
+
+	var action service.ModerationAction
+
+	actions, err := action.GetAll(filters, options.Find().SetLimit(config.ModerationPageSize))
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error getting moderation actions -> %s", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": actions})
+}
+
+// MergeQuestions is the handler for the moderator to merge duplicate questions into the question in the request path
+func MergeQuestions(c *gin.Context) {
+	var req questionMerge
+
+	err := c.ShouldBind(&req)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing the request body -> %s", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	question, ok := getQuestion(c)
+	if !ok {
+		return
+	}
+
+	moderator, ok := authorizeModerator(c)
+	if !ok {
+		return
+	}
+
+	duplicates, err := question.GetAll([]bson.E{{"_id", bson.D{{"$in", req.DuplicateIDs}}}})
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error getting duplicates of question [%s] -> %s", question.ID.Hex(), err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if len(duplicates) != len(req.DuplicateIDs) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Some of the duplicate questions were not found"})
+		return
+	}
+
+	for _, duplicate := range duplicates {
+		if duplicate.ID == question.ID || duplicate.SkillID != question.SkillID {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Duplicates must be other questions of the same skill"})
+			return
+		}
+	}
+
+	err = question.MergeDuplicates(duplicates)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error merging duplicates into question [%s] -> %s", question.ID.Hex(), err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	var (
+		revision service.Revision
+		flag     service.Flag
+	)
+
+	err = revision.DeleteAll(req.DuplicateIDs)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error deleting revisions of merged duplicates -> %s", err.Error()))
+	}
+
+	authorIDs := []primitive.ObjectID{question.UserID}
+
+	for _, duplicate := range duplicates {
+		authorIDs = append(authorIDs, duplicate.UserID)
+
+		err = flag.Resolve(duplicate.ID)
+		if err != nil {
+			logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error resolving flags of merged duplicate [%s] -> %s", duplicate.ID.Hex(), err.Error()))
+		}
+
+		action := service.ModerationAction{
+			Action:        config.ModerationMerge,
+			PostID:        duplicate.ID,
+			PostType:      config.QuestionPost,
+			TargetUserID:  duplicate.UserID,
+			ModeratorID:   moderator.ID,
+			ModeratorName: moderator.Username,
+			Reason:        fmt.Sprintf("Merged into question [%s]", question.ID.Hex()),
+		}
+
+		err = action.Add()
+		if err != nil {
+			logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error recording merge of duplicate [%s] -> %s", duplicate.ID.Hex(), err.Error()))
+		}
+	}
+
+	// Upvotes and accepted answers moved between questions change their authors' reputation
+	var answer service.Answer
+
+	answers, err := answer.GetAll([]bson.E{{"question_id", question.ID}})
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error getting answers of question [%s] -> %s", question.ID.Hex(), err.Error()))
+	}
+
+	for _, ans := range answers {
+		authorIDs = append(authorIDs, ans.UserID)
+	}
+
+	go recalculateReputation(authorIDs...)
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"questionID": question.ID, "merged": len(duplicates)}})
+}
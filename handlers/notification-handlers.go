@@ -0,0 +1,356 @@
+package handlers
+
+import (
+	"career-compass-go/config"
+	"career-compass-go/pkg/events"
+	"career-compass-go/pkg/logging"
+	"career-compass-go/service"
+	"career-compass-go/utils"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"net/http"
+	"runtime"
+)
+
+// notificationSelection holds the notifications to mark, all of them when empty
+type notificationSelection struct {
+	NotificationIDs []primitive.ObjectID `json:"notificationIDs" binding:"max=100"`
+}
+
+// FollowQuestion is the handler for the user to subscribe to the activity of a question
+func FollowQuestion(c *gin.Context) {
+	setQuestionSubscription(c, true)
+}
+
+// UnfollowQuestion is the handler for the user to unsubscribe from the activity of a question
+func UnfollowQuestion(c *gin.Context) {
+	setQuestionSubscription(c, false)
+}
+
+// setQuestionSubscription subscribes or unsubscribes the user to the question in the request path
+func setQuestionSubscription(c *gin.Context, follow bool) {
+	question, ok := getQuestion(c)
+	if !ok {
+		return
+	}
+
+	setSubscription(c, question.ID, config.SubscriptionQuestion, follow)
+}
+
+// FollowSkill is the handler for the user to subscribe to the new questions of a skill
+func FollowSkill(c *gin.Context) {
+	setSkillSubscription(c, true)
+}
+
+// UnfollowSkill is the handler for the user to unsubscribe from the new questions of a skill
+func UnfollowSkill(c *gin.Context) {
+	setSkillSubscription(c, false)
+}
+
+// setSkillSubscription subscribes or unsubscribes the user to the skill in the request path
+func setSkillSubscription(c *gin.Context, follow bool) {
+	skillID := c.Param("id")
+
+	skillObjectID, err := primitive.ObjectIDFromHex(skillID)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing skillID to object -> %s", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var skill service.Skill
+
+	err = skill.Get([]bson.E{{"_id", skillObjectID}})
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Skill not found"})
+			return
+		}
+
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error getting skill [%s] -> %s", skillID, err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	setSubscription(c, skill.ID, config.SubscriptionSkill, follow)
+}
+
+// setSubscription subscribes or unsubscribes the current user to the target and responds with the subscription state
+func setSubscription(c *gin.Context, targetID primitive.ObjectID, targetType string, follow bool) {
+	userObjectID, err := primitive.ObjectIDFromHex(c.GetString("userID"))
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing userID to object -> %s", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	subscription := service.Subscription{
+		UserID:     userObjectID,
+		TargetID:   targetID,
+		TargetType: targetType,
+	}
+
+	if follow {
+		err = subscription.Add()
+	} else {
+		err = subscription.Delete(userObjectID, targetID)
+	}
+
+	// Unfollowing something not followed leaves the user unsubscribed either way
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"targetID": targetID, "targetType": targetType, "following": follow}})
+}
+
+// GetSubscriptions is the handler to get the questions and skills followed by the current user
+func GetSubscriptions(c *gin.Context) {
+	userObjectID, err := primitive.ObjectIDFromHex(c.GetString("userID"))
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing userID to object -> %s", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	var subscription service.Subscription
+
+	subscriptions, err := subscription.GetAll([]bson.E{{"user_id", userObjectID}})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": subscriptions})
+}
+
+// GetNotifications is the handler to get the current user's notifications, optionally only the unread ones
+func GetNotifications(c *gin.Context) {
+	var query service.NotificationQuery
+
+	err := c.ShouldBindQuery(&query)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing notification query -> %s", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userObjectID, err := primitive.ObjectIDFromHex(c.GetString("userID"))
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing userID to object -> %s", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	filters := []bson.E{{"user_id", userObjectID}}
+	if query.Unread {
+		filters = append(filters, bson.E{"read", false})
+	}
+
+	var notification service.Notification
+
+	notifications, err := notification.GetAll(filters, options.Find().SetSkip((query.Page-1)*query.Limit).SetLimit(query.Limit))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	total, err := notification.Count(filters)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	unread, err := notification.Count([]bson.E{{"user_id", userObjectID}, {"read", false}})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"notifications": notifications, "page": query.Page, "limit": query.Limit, "total": total, "unread": unread}})
+}
+
+// GetNotificationCounts is the handler to get the total and unread notification counts of the current user
+func GetNotificationCounts(c *gin.Context) {
+	userObjectID, err := primitive.ObjectIDFromHex(c.GetString("userID"))
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing userID to object -> %s", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	var notification service.Notification
+
+	total, err := notification.Count([]bson.E{{"user_id", userObjectID}})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	unread, err := notification.Count([]bson.E{{"user_id", userObjectID}, {"read", false}})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"total": total, "unread": unread}})
+}
+
+// MarkNotificationsRead is the handler to mark the given notifications, or all of them, as read
+func MarkNotificationsRead(c *gin.Context) {
+	setNotificationsRead(c, true)
+}
+
+// MarkNotificationsUnread is the handler to mark the given notifications, or all of them, as unread
+func MarkNotificationsUnread(c *gin.Context) {
+	setNotificationsRead(c, false)
+}
+
+// setNotificationsRead updates the read state of the current user's notifications in the request body
+func setNotificationsRead(c *gin.Context, read bool) {
+	var selection notificationSelection
+
+	// An empty body selects every notification
+	if c.Request.ContentLength > 0 {
+		err := c.ShouldBind(&selection)
+		if err != nil {
+			logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing the request body -> %s", err.Error()))
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	userObjectID, err := primitive.ObjectIDFromHex(c.GetString("userID"))
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing userID to object -> %s", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	var notification service.Notification
+
+	updated, err := notification.SetRead(userObjectID, selection.NotificationIDs, read)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"updated": updated, "read": read}})
+}
+
+// subscribe subscribes the user to the target, logging rather than failing the request it is part of
+func subscribe(userID, targetID primitive.ObjectID, targetType string) {
+	subscription := service.Subscription{
+		UserID:     userID,
+		TargetID:   targetID,
+		TargetType: targetType,
+	}
+
+	err := subscription.Add()
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error auto-subscribing user [%s] to %s [%s] -> %s", userID.Hex(), targetType, targetID.Hex(), err.Error()))
+	}
+}
+
+// notify sends the notification to the subscribers of the targets and the other given recipients, except its actor
+func notify(notification service.Notification, targetIDs []primitive.ObjectID, recipientIDs ...primitive.ObjectID) {
+	var (
+		subscription  service.Subscription
+		subscriberIDs []primitive.ObjectID
+		err           error
+	)
+
+	if len(targetIDs) > 0 {
+		subscriberIDs, err = subscription.GetSubscriberIDs(targetIDs)
+		if err != nil {
+			logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error getting subscribers for [%s] notification -> %s", notification.Type, err.Error()))
+			return
+		}
+	}
+
+	seen := map[primitive.ObjectID]struct{}{notification.ActorID: {}}
+	recipients := make([]primitive.ObjectID, 0, len(subscriberIDs)+len(recipientIDs))
+
+	for _, recipientID := range append(subscriberIDs, recipientIDs...) {
+		if _, ok := seen[recipientID]; ok || recipientID.IsZero() {
+			continue
+		}
+
+		seen[recipientID] = struct{}{}
+		recipients = append(recipients, recipientID)
+	}
+
+	notifications, err := notification.AddAll(recipients)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error sending [%s] notification -> %s", notification.Type, err.Error()))
+		return
+	}
+
+	for _, sent := range notifications {
+		events.Default.Publish(events.UserTopic(sent.UserID.Hex()), config.EventNotification, sent)
+	}
+}
+
+// notifyStatusChange notifies the subscribers of the question that its status changed
+func notifyStatusChange(question *service.Question, status string, actorID primitive.ObjectID, actorName string) {
+	notification := service.Notification{
+		Type:       config.NotificationStatus,
+		ActorID:    actorID,
+		ActorName:  actorName,
+		SkillID:    question.SkillID,
+		QuestionID: question.ID,
+		Message:    fmt.Sprintf("\"%s\" was marked %s", question.Title, status),
+	}
+
+	notify(notification, []primitive.ObjectID{question.ID})
+}
+
+// notifyMentions notifies the users mentioned in a question or answer, skipping those already mentioned before an edit
+func notifyMentions(actorID primitive.ObjectID, actorName string, questionID, postID primitive.ObjectID, mentions, previous []service.Mention) {
+	notified := make(map[primitive.ObjectID]struct{}, len(previous))
+	for _, mention := range previous {
+		notified[mention.UserID] = struct{}{}
+	}
+
+	recipientIDs := make([]primitive.ObjectID, 0, len(mentions))
+	for _, mention := range mentions {
+		if _, ok := notified[mention.UserID]; !ok {
+			recipientIDs = append(recipientIDs, mention.UserID)
+		}
+	}
+
+	if len(recipientIDs) == 0 {
+		return
+	}
+
+	var question service.Question
+
+	err := question.Get([]bson.E{{"_id", questionID}})
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error getting question [%s] of mention notification -> %s", questionID.Hex(), err.Error()))
+		return
+	}
+
+	message := fmt.Sprintf("%s mentioned you in \"%s\"", actorName, question.Title)
+	if postID != questionID {
+		message = fmt.Sprintf("%s mentioned you in an answer to \"%s\"", actorName, question.Title)
+	}
+
+	notification := service.Notification{
+		Type:       config.NotificationMention,
+		ActorID:    actorID,
+		ActorName:  actorName,
+		SkillID:    question.SkillID,
+		QuestionID: question.ID,
+		PostID:     postID,
+		Message:    message,
+	}
+
+	notify(notification, nil, recipientIDs...)
+}
@@ -0,0 +1,545 @@
+package handlers
+
+import (
+	"career-compass-go/config"
+	"career-compass-go/pkg/logging"
+	"career-compass-go/pkg/markdown"
+	"career-compass-go/service"
+	"career-compass-go/utils"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"net/http"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// postEdit holds the editable fields of a question or answer
+type postEdit struct {
+	Title   string   `json:"title"`
+	Content string   `json:"content" binding:"required"`
+	Tags    []string `json:"tags" binding:"max=5,dive,min=1,max=30"`
+}
+
+// EditQuestion is the handler for the author or a moderator to edit a question
+func EditQuestion(c *gin.Context) {
+	var edit postEdit
+
+	err := c.ShouldBind(&edit)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing the request body -> %s", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	question, ok := getQuestion(c)
+	if !ok {
+		return
+	}
+
+	user, ok := authorizeAuthor(c, question.UserID)
+	if !ok {
+		return
+	}
+
+	if edit.Title == "" {
+		edit.Title = question.Title
+	}
+
+	contentHTML, mentions, unresolved, err := renderContent(edit.Content)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error rendering question content -> %s", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Keep the replaced version in the post's revision history
+	revision := service.Revision{
+		PostID:       question.ID,
+		PostType:     config.QuestionPost,
+		Title:        question.Title,
+		Content:      question.Content,
+		EditedBy:     user.ID,
+		EditedByName: user.Username,
+		CreatedAt:    time.Now(),
+	}
+
+	err = revision.Add()
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error saving revision of question [%s] -> %s", question.ID.Hex(), err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	setFields := bson.D{
+		{"title", edit.Title},
+		{"content", edit.Content},
+		{"content_html", contentHTML},
+		{"mentions", mentions},
+		{"unresolved_mentions", unresolved},
+		{"updated_at", revision.CreatedAt},
+	}
+
+	if edit.Tags != nil {
+		setFields = append(setFields, bson.E{"tags", utils.NormalizeTags(edit.Tags)})
+	}
+
+	updateFields := bson.D{
+		{"$set", setFields},
+	}
+
+	err = question.Update(question.ID, updateFields)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error updating question details -> %s", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	go notifyMentions(user.ID, user.Username, question.ID, question.ID, mentions, question.Mentions)
+
+	c.JSON(http.StatusOK, gin.H{"data": "Question updated successfully"})
+}
+
+// DeleteQuestion is the handler for the author or a moderator to delete a question with its answers
+func DeleteQuestion(c *gin.Context) {
+	question, ok := getQuestion(c)
+	if !ok {
+		return
+	}
+
+	_, ok = authorizeAuthor(c, question.UserID)
+	if !ok {
+		return
+	}
+
+	err := removeQuestion(question)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": "Question deleted successfully"})
+}
+
+// removeQuestion deletes a question along with its answers, revisions, comments and the references to them
+func removeQuestion(question *service.Question) error {
+	var answer service.Answer
+
+	answers, err := answer.GetAll([]bson.E{{"question_id", question.ID}})
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error getting answers of question [%s] -> %s", question.ID.Hex(), err.Error()))
+		return err
+	}
+
+	err = question.Delete(question.ID)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error deleting question [%s] -> %s", question.ID.Hex(), err.Error()))
+		return err
+	}
+
+	postIDs := []primitive.ObjectID{question.ID}
+	for _, ans := range answers {
+		postIDs = append(postIDs, ans.ID)
+	}
+
+	var revision service.Revision
+
+	err = revision.DeleteAll(postIDs)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error deleting revisions of question [%s] -> %s", question.ID.Hex(), err.Error()))
+	}
+
+	var comment service.Comment
+
+	err = comment.DeleteAll(postIDs)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error deleting comments of question [%s] -> %s", question.ID.Hex(), err.Error()))
+	}
+
+	removePostReferences(postIDs)
+
+	authorIDs := []primitive.ObjectID{question.UserID}
+	for _, ans := range answers {
+		authorIDs = append(authorIDs, ans.UserID)
+	}
+
+	go recalculateReputation(authorIDs...)
+
+	return nil
+}
+
+// removePostReferences deletes the bookmarks, subscriptions, notifications and pending flags pointing at the removed posts
+func removePostReferences(postIDs []primitive.ObjectID) {
+	var bookmark service.Bookmark
+
+	err := bookmark.DeleteAll(postIDs)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error deleting bookmarks of removed posts -> %s", err.Error()))
+	}
+
+	var subscription service.Subscription
+
+	err = subscription.DeleteAll(postIDs)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error deleting subscriptions of removed posts -> %s", err.Error()))
+	}
+
+	var notification service.Notification
+
+	err = notification.DeleteAll(postIDs)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error deleting notifications of removed posts -> %s", err.Error()))
+	}
+
+	var flag service.Flag
+
+	err = flag.DeleteAllPending(postIDs)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error deleting pending flags of removed posts -> %s", err.Error()))
+	}
+}
+
+// EditAnswer is the handler for the author or a moderator to edit an answer
+func EditAnswer(c *gin.Context) {
+	var edit postEdit
+
+	err := c.ShouldBind(&edit)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing the request body -> %s", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	answer, ok := getAnswer(c)
+	if !ok {
+		return
+	}
+
+	user, ok := authorizeAuthor(c, answer.UserID)
+	if !ok {
+		return
+	}
+
+	contentHTML, mentions, unresolved, err := renderContent(edit.Content)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error rendering answer content -> %s", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Keep the replaced version in the post's revision history
+	revision := service.Revision{
+		PostID:       answer.ID,
+		PostType:     config.AnswerPost,
+		Content:      answer.Content,
+		EditedBy:     user.ID,
+		EditedByName: user.Username,
+		CreatedAt:    time.Now(),
+	}
+
+	err = revision.Add()
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error saving revision of answer [%s] -> %s", answer.ID.Hex(), err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	updateFields := bson.D{
+		{"$set", bson.D{
+			{"content", edit.Content},
+			{"content_html", contentHTML},
+			{"mentions", mentions},
+			{"unresolved_mentions", unresolved},
+			{"updated_at", revision.CreatedAt},
+		}},
+	}
+
+	err = answer.Update(answer.ID, updateFields)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error updating answer details -> %s", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	go notifyMentions(user.ID, user.Username, answer.QuestionID, answer.ID, mentions, answer.Mentions)
+
+	c.JSON(http.StatusOK, gin.H{"data": "Answer updated successfully"})
+}
+
+// DeleteAnswer is the handler for the author or a moderator to delete an answer
+func DeleteAnswer(c *gin.Context) {
+	answer, ok := getAnswer(c)
+	if !ok {
+		return
+	}
+
+	_, ok = authorizeAuthor(c, answer.UserID)
+	if !ok {
+		return
+	}
+
+	err := removeAnswer(answer)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": "Answer deleted successfully"})
+}
+
+// removeAnswer deletes an answer along with its revisions, comments and the references to it and updates its question
+func removeAnswer(answer *service.Answer) error {
+	err := answer.Delete(answer.ID)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error deleting answer [%s] -> %s", answer.ID.Hex(), err.Error()))
+		return err
+	}
+
+	updateFields := bson.D{
+		{"$inc", bson.D{{"answer_count", -1}}},
+	}
+
+	// The question is no longer resolved once its accepted answer is gone
+	if answer.Accepted {
+		updateFields = append(updateFields, bson.E{"$set", bson.D{
+			{"status", config.QuestionUnresolved},
+			{"updated_at", time.Now()},
+		}})
+	}
+
+	var question service.Question
+
+	err = question.Update(answer.QuestionID, updateFields)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error updating question [%s] of the deleted answer -> %s", answer.QuestionID.Hex(), err.Error()))
+		return err
+	}
+
+	var revision service.Revision
+
+	err = revision.DeleteAll([]primitive.ObjectID{answer.ID})
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error deleting revisions of answer [%s] -> %s", answer.ID.Hex(), err.Error()))
+	}
+
+	var comment service.Comment
+
+	err = comment.DeleteAll([]primitive.ObjectID{answer.ID})
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error deleting comments of answer [%s] -> %s", answer.ID.Hex(), err.Error()))
+	}
+
+	removePostReferences([]primitive.ObjectID{answer.ID})
+
+	go recalculateReputation(answer.UserID)
+
+	return nil
+}
+
+// GetQuestionRevisions is the handler for fetching the revision history of a question
+func GetQuestionRevisions(c *gin.Context) {
+	getRevisions(c, config.QuestionPost)
+}
+
+// GetAnswerRevisions is the handler for fetching the revision history of an answer
+func GetAnswerRevisions(c *gin.Context) {
+	getRevisions(c, config.AnswerPost)
+}
+
+// getRevisions responds with the revision history of the post in the request path
+func getRevisions(c *gin.Context, postType string) {
+	postID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing %sID to object -> %s", postType, err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var revision service.Revision
+
+	revisions, err := revision.GetAll([]bson.E{{"post_id", postID}, {"post_type", postType}})
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error getting revisions of %s [%s] -> %s", postType, postID.Hex(), err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": revisions})
+}
+
+// PreviewContent is the handler to render Markdown content exactly as it would be published
+func PreviewContent(c *gin.Context) {
+	var edit postEdit
+
+	err := c.ShouldBind(&edit)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing the request body -> %s", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	contentHTML, _, unresolved, err := renderContent(edit.Content)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error rendering preview content -> %s", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"contentHTML": contentHTML, "unresolvedMentions": unresolved}})
+}
+
+// renderContent renders the Markdown content of a question or answer, linking the users it mentions.
+// The mentioned names matching no user or several users are returned unresolved
+func renderContent(content string) (string, []service.Mention, []string, error) {
+	mentions := make([]service.Mention, 0)
+	unresolved := make([]string, 0)
+
+	names := markdown.Mentions(content)
+	if len(names) == 0 {
+		contentHTML, err := markdown.Render(content)
+		return contentHTML, mentions, unresolved, err
+	}
+
+	var user service.User
+
+	users, err := user.GetByUsernames(names)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	matches := make(map[string][]service.User, len(users))
+	for _, u := range users {
+		key := strings.ToLower(u.Username)
+		matches[key] = append(matches[key], u)
+	}
+
+	// Usernames are not unique, so only the names matching a single user are resolved
+	links := make(map[string]string, len(names))
+	for _, name := range names {
+		key := strings.ToLower(name)
+		if len(matches[key]) != 1 {
+			unresolved = append(unresolved, name)
+			continue
+		}
+
+		mentioned := matches[key][0]
+		links[key] = fmt.Sprintf(config.MentionProfilePath, mentioned.ID.Hex())
+		mentions = append(mentions, service.Mention{UserID: mentioned.ID, Username: mentioned.Username})
+	}
+
+	contentHTML, err := markdown.RenderWithMentions(content, links)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	return contentHTML, mentions, unresolved, nil
+}
+
+// getQuestion fetches the question in the request path
+func getQuestion(c *gin.Context) (*service.Question, bool) {
+	questionID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing questionID to object -> %s", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+
+	var question service.Question
+
+	err = question.Get([]bson.E{{"_id", questionID}})
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Question not found"})
+			return nil, false
+		}
+
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error getting question [%s] -> %s", questionID.Hex(), err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, false
+	}
+
+	return &question, true
+}
+
+// getAnswer fetches the answer in the request path
+func getAnswer(c *gin.Context) (*service.Answer, bool) {
+	answerID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing answerID to object -> %s", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+
+	var answer service.Answer
+
+	err = answer.Get([]bson.E{{"_id", answerID}})
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Answer not found"})
+			return nil, false
+		}
+
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error getting answer [%s] -> %s", answerID.Hex(), err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, false
+	}
+
+	return &answer, true
+}
+
+// getCurrentUser fetches the user of the verified token
+func getCurrentUser(c *gin.Context) (*service.User, bool) {
+	userID, err := primitive.ObjectIDFromHex(c.GetString("userID"))
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing userID to object -> %s", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, false
+	}
+
+	var user service.User
+
+	err = user.Get([]bson.E{{"_id", userID}})
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error getting user details -> %s", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, false
+	}
+
+	return &user, true
+}
+
+// authorizeAuthor verifies that the current user is the given author or a moderator
+func authorizeAuthor(c *gin.Context, authorID primitive.ObjectID) (*service.User, bool) {
+	user, ok := getCurrentUser(c)
+	if !ok {
+		return nil, false
+	}
+
+	if user.ID != authorID && user.Role != config.ModeratorRole {
+		logging.Logger.Info(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("User [%s] is neither the author [%s] nor a moderator", user.ID.Hex(), authorID.Hex()))
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the author or a moderator can modify this"})
+		return nil, false
+	}
+
+	return user, true
+}
+
+// authorizeModerator verifies that the current user is a moderator
+func authorizeModerator(c *gin.Context) (*service.User, bool) {
+	user, ok := getCurrentUser(c)
+	if !ok {
+		return nil, false
+	}
+
+	if user.Role != config.ModeratorRole {
+		logging.Logger.Info(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("User [%s] is not a moderator", user.ID.Hex()))
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only a moderator can perform this action"})
+		return nil, false
+	}
+
+	return user, true
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"career-compass-go/config"
+	"career-compass-go/pkg/logging"
+	"career-compass-go/service"
+	"career-compass-go/utils"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"net/http"
+	"runtime"
+)
+
+// GetMyProfile is the handler to get the profile of the current user
+func GetMyProfile(c *gin.Context) {
+	userObjectID, err := primitive.ObjectIDFromHex(c.GetString("userID"))
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing userID to object -> %s", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	getProfile(c, userObjectID)
+}
+
+// GetUserProfile is the handler to get the public profile of a user with their reputation and badges
+func GetUserProfile(c *gin.Context) {
+	userID := c.Param("id")
+
+	userObjectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing userID to object -> %s", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	getProfile(c, userObjectID)
+}
+
+// getProfile responds with the profile of the given user
+func getProfile(c *gin.Context, userID primitive.ObjectID) {
+	var (
+		user       service.User
+		reputation service.Reputation
+	)
+
+	err := user.Get([]bson.E{{"_id", userID}})
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+			return
+		}
+
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error getting user [%s] -> %s", userID.Hex(), err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	reputations, err := reputation.GetAll([]bson.E{{"user_id", userID}})
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error getting reputations of user [%s] -> %s", userID.Hex(), err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	profile := service.Profile{
+		UserID:      user.ID,
+		Username:    user.Username,
+		Reputation:  user.Reputation,
+		Badges:      user.Badges,
+		Reputations: reputations,
+	}
+
+	if profile.Badges == nil {
+		profile.Badges = []service.Badge{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": profile})
+}
+
+// GetSkillLeaderboard is the handler to get the users with the highest reputation within a skill
+func GetSkillLeaderboard(c *gin.Context) {
+	skillID := c.Param("id")
+
+	skillObjectID, err := primitive.ObjectIDFromHex(skillID)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing skillID to object -> %s", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var reputation service.Reputation
+
+	leaderboard, err := reputation.GetLeaderboard(skillObjectID, config.LeaderboardSize)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error getting leaderboard of skill [%s] -> %s", skillID, err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": leaderboard})
+}
+
+// recalculateReputation refreshes the reputation and badges of the authors whose posts were voted on, accepted or deleted
+func recalculateReputation(userIDs ...primitive.ObjectID) {
+	var reputation service.Reputation
+
+	err := reputation.Recalculate(userIDs)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error recalculating reputation of users %v -> %s", userIDs, err.Error()))
+	}
+}
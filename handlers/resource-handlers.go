@@ -0,0 +1,288 @@
+package handlers
+
+import (
+	"career-compass-go/pkg/logging"
+	"career-compass-go/service"
+	"career-compass-go/utils"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"net/http"
+	"runtime"
+	"time"
+)
+
+// GetResources is the handler for fetching the learning resources of a skill ranked by usefulness
+func GetResources(c *gin.Context) {
+	skillID := c.Param("id")
+
+	skillObjectID, err := primitive.ObjectIDFromHex(skillID)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing skillID to object -> %s", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	filters := []bson.E{
+		{"skill_id", skillObjectID},
+	}
+
+	if resourceType := c.Query("type"); resourceType != "" {
+		filters = append(filters, bson.E{"type", resourceType})
+	}
+
+	var resource service.Resource
+
+	resources, err := resource.GetRanked(filters)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error getting resources for skill [%s] -> %s", skillID, err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": resources})
+}
+
+// AddResource is the handler to add a new learning resource to a skill
+func AddResource(c *gin.Context) {
+	skillID := c.Param("id")
+
+	skillObjectID, err := primitive.ObjectIDFromHex(skillID)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing skillID to object -> %s", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var resource service.Resource
+
+	err = c.ShouldBind(&resource)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing the request body -> %s", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var skill service.Skill
+
+	err = skill.Get([]bson.E{{"_id", skillObjectID}})
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Skill not found"})
+			return
+		}
+
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error getting skill details for skill [%s] -> %s", skillID, err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	resource.AddedBy, err = primitive.ObjectIDFromHex(c.GetString("userID"))
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing userID to object -> %s", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	currTime := time.Now()
+
+	resource.SkillID = skillObjectID
+	resource.Ratings = []service.ResourceRating{}
+	resource.RatingAverage = 0
+	resource.RatingCount = 0
+	resource.CreatedAt = currTime
+	resource.UpdatedAt = currTime
+
+	err = resource.Add()
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error creating resource details -> %s", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"resourceID": resource.ID}})
+}
+
+// UpdateResource is the handler to update a learning resource added by the user
+func UpdateResource(c *gin.Context) {
+	var resource service.Resource
+
+	err := c.ShouldBind(&resource)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing the request body -> %s", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	existing, ok := getOwnedResource(c)
+	if !ok {
+		return
+	}
+
+	setFields := bson.D{
+		{"title", resource.Title},
+		{"url", resource.URL},
+		{"type", resource.Type},
+		{"provider", resource.Provider},
+		{"duration_minutes", resource.DurationMinutes},
+		{"cost", resource.Cost},
+		{"difficulty", resource.Difficulty},
+		{"language", resource.Language},
+		{"updated_at", time.Now()},
+	}
+
+	updateFields := bson.D{}
+
+	// A new link starts its checks afresh, the old link's failures must not keep it hidden
+	if resource.URL != existing.URL {
+		setFields = append(setFields, bson.E{"broken", false}, bson.E{"link_failures", 0})
+		updateFields = append(updateFields, bson.E{"$unset", bson.D{{"link_checks", ""}, {"link_checked_at", ""}}})
+	}
+
+	updateFields = append(updateFields, bson.E{"$set", setFields})
+
+	err = resource.Update(existing.ID, updateFields)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error updating resource details -> %s", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": "Resource updated successfully"})
+}
+
+// DeleteResource is the handler to delete a learning resource added by the user
+func DeleteResource(c *gin.Context) {
+	existing, ok := getOwnedResource(c)
+	if !ok {
+		return
+	}
+
+	err := existing.Delete(existing.ID)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error deleting resource -> %s", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": "Resource deleted successfully"})
+}
+
+// RateResource is the handler for the user to rate the usefulness of a learning resource
+func RateResource(c *gin.Context) {
+	var rating service.ResourceRating
+
+	err := c.ShouldBind(&rating)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing the request body -> %s", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resourceID, err := primitive.ObjectIDFromHex(c.Param("resourceID"))
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing resourceID to object -> %s", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rating.UserID, err = primitive.ObjectIDFromHex(c.GetString("userID"))
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing userID to object -> %s", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	rating.RatedAt = time.Now()
+
+	var resource service.Resource
+
+	err = resource.Rate(resourceID, rating)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Resource not found"})
+			return
+		}
+
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error rating resource -> %s", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": "Resource rated successfully"})
+}
+
+// GetBrokenResources is the handler for the moderators' report of learning resources with broken links
+func GetBrokenResources(c *gin.Context) {
+	_, ok := authorizeModerator(c)
+	if !ok {
+		return
+	}
+
+	filters := []bson.E{
+		{"broken", true},
+	}
+
+	if skillID := c.Query("skillID"); skillID != "" {
+		skillObjectID, err := primitive.ObjectIDFromHex(skillID)
+		if err != nil {
+			logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing skillID to object -> %s", err.Error()))
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		filters = append(filters, bson.E{"skill_id", skillObjectID})
+	}
+
+	var resource service.Resource
+
+	resources, err := resource.GetRanked(filters)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error getting broken resources -> %s", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"count": len(resources), "resources": resources}})
+}
+
+// getOwnedResource fetches the resource in the request path and verifies that it was added by the user or a moderator
+func getOwnedResource(c *gin.Context) (*service.Resource, bool) {
+	skillID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing skillID to object -> %s", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+
+	resourceID, err := primitive.ObjectIDFromHex(c.Param("resourceID"))
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing resourceID to object -> %s", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+
+	var resource service.Resource
+
+	err = resource.Get([]bson.E{{"_id", resourceID}, {"skill_id", skillID}})
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Resource not found"})
+			return nil, false
+		}
+
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error getting resource [%s] -> %s", resourceID.Hex(), err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, false
+	}
+
+	_, ok := authorizeAuthor(c, resource.AddedBy)
+	if !ok {
+		return nil, false
+	}
+
+	return &resource, true
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"career-compass-go/config"
 	"career-compass-go/pkg/logging"
+	"career-compass-go/service"
 	"career-compass-go/utils"
 	"fmt"
 	gomail "gopkg.in/mail.v2"
@@ -11,7 +12,7 @@ import (
 )
 
 // SendMail sends a mail using SMTP server
-func SendMail(mailTopic string, mail string, data any) {
+func SendMail(mailTopic string, mail string, data any) error {
 	m := gomail.NewMessage()
 
 	m.SetHeader("From", config.SMTPEmail)
@@ -29,11 +30,27 @@ func SendMail(mailTopic string, mail string, data any) {
 
 		if err != nil {
 			logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error executing otp mail template -> %s", err.Error()))
-			return
+			return err
 		}
 
 		m.SetHeader("Subject", "Career Compass - OTP Authentication")
 		m.SetBody("text/html", body.String())
+	case config.MailDigest:
+		var body bytes.Buffer
+
+		digest := data.(service.Digest)
+
+		err := config.Templates.ExecuteTemplate(&body, "digestTemplate.html", digest)
+		if err != nil {
+			logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error executing digest mail template -> %s", err.Error()))
+			return err
+		}
+
+		// One-click unsubscribe for mail clients that support it
+		m.SetHeader("List-Unsubscribe", fmt.Sprintf("<%s>", digest.UnsubscribeURL))
+		m.SetHeader("List-Unsubscribe-Post", "List-Unsubscribe=One-Click")
+		m.SetHeader("Subject", fmt.Sprintf("Career Compass - Your %s digest", digest.Frequency))
+		m.SetBody("text/html", body.String())
 	}
 
 	dialer := gomail.NewDialer(
@@ -46,8 +63,10 @@ func SendMail(mailTopic string, mail string, data any) {
 	err := dialer.DialAndSend(m)
 	if err != nil {
 		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error sending mail -> %s", err.Error()))
-		return
+		return err
 	}
 
 	logging.Logger.Info(utils.GetFrame(runtime.Caller(0)), "Mail sent successfully!")
+
+	return nil
 }
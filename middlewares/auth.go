@@ -2,9 +2,15 @@ package middlewares
 
 import (
 	"career-compass-go/config"
+	"career-compass-go/service"
+	"errors"
+	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 	"net/http"
+	"time"
 )
 
 // VerifyToken middleware verifies the validity and authenticity of a JWT token
@@ -29,9 +35,48 @@ func VerifyToken() gin.HandlerFunc {
 			return
 		}
 
+		// Single purpose tokens such as digest unsubscribe links do not grant a session
+		if _, ok := claims["purpose"]; ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+			c.Abort()
+			return
+		}
+
 		c.Set("userID", claims["userID"].(string))
 		c.Set("email", claims["email"].(string))
 
+		// Suspended users keep valid tokens, so the suspension is checked on every request
+		userID, err := primitive.ObjectIDFromHex(claims["userID"].(string))
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+			c.Abort()
+			return
+		}
+
+		var user service.User
+
+		err = user.GetSuspension(userID)
+		if err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+				c.Abort()
+				return
+			}
+
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			c.Abort()
+			return
+		}
+
+		if user.SuspendedUntil.After(time.Now()) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":  fmt.Sprintf("Account suspended until %s", user.SuspendedUntil.Format(time.RFC3339)),
+				"reason": user.SuspensionReason,
+			})
+			c.Abort()
+			return
+		}
+
 		c.Next()
 	}
 }
@@ -0,0 +1,98 @@
+package digest
+
+import (
+	"career-compass-go/auth"
+	"career-compass-go/config"
+	"career-compass-go/mailer"
+	"career-compass-go/pkg/logging"
+	"career-compass-go/service"
+	"career-compass-go/utils"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"net/url"
+	"runtime"
+	"time"
+)
+
+// periods maps the digest frequencies to the time covered by each digest
+var periods = map[string]time.Duration{
+	config.DigestDaily:  24 * time.Hour,
+	config.DigestWeekly: 7 * 24 * time.Hour,
+}
+
+// Start periodically sends the digests that are due until the process exits
+func Start() {
+	for {
+		Run(time.Now())
+		time.Sleep(config.DigestCheckInterval)
+	}
+}
+
+// Run emails every user whose daily or weekly digest is due a summary of the skills they follow
+func Run(now time.Time) {
+	for frequency, period := range periods {
+		var user service.User
+
+		users, err := user.GetDigestRecipients(frequency, now.Add(-period))
+		if err != nil {
+			logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error getting [%s] digest recipients -> %s", frequency, err.Error()))
+			continue
+		}
+
+		sent := 0
+		for _, recipient := range users {
+			ok, err := send(recipient, frequency, now.Add(-period))
+			if err != nil {
+				logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error sending [%s] digest to user [%s] -> %s", frequency, recipient.ID.Hex(), err.Error()))
+				continue
+			}
+
+			if ok {
+				sent++
+			}
+		}
+
+		logging.Logger.Info(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Sent %d [%s] digests", sent, frequency))
+	}
+}
+
+// send builds and emails the user's digest covering the time since their last one, skipping empty digests
+func send(user service.User, frequency string, periodStart time.Time) (bool, error) {
+	since := periodStart
+	if user.DigestSentAt.After(since) {
+		since = user.DigestSentAt
+	}
+
+	digest := service.Digest{
+		Username:  user.Username,
+		Frequency: frequency,
+	}
+
+	err := digest.Build(user.ID, since)
+	if err != nil {
+		return false, err
+	}
+
+	if !digest.IsEmpty() {
+		token, err := auth.GenerateUnsubscribeToken(user.ID.Hex())
+		if err != nil {
+			return false, err
+		}
+
+		digest.UnsubscribeURL = fmt.Sprintf("%s/digest/unsubscribe?token=%s", config.APIBaseURL, url.QueryEscape(token))
+
+		// A failed mail leaves the digest due, so the next run retries it
+		err = mailer.SendMail(config.MailDigest, user.Email, digest)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	// Empty digests also count as sent so the next one covers only the following period
+	err = user.Update([]bson.E{{"_id", user.ID}}, bson.D{{"$set", bson.D{{"digest_sent_at", time.Now()}}}})
+	if err != nil {
+		return false, err
+	}
+
+	return !digest.IsEmpty(), nil
+}
@@ -0,0 +1,147 @@
+package events
+
+import (
+	"career-compass-go/config"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Event is a piece of Q&A activity pushed to the clients listening on its topic
+type Event struct {
+	ID        uint64
+	Topic     string
+	Type      string
+	Data      any
+	CreatedAt time.Time
+}
+
+// Subscription receives the events published to a topic until it is closed
+type Subscription struct {
+	Events <-chan Event
+
+	topic  string
+	events chan Event
+	bus    *Bus
+	once   sync.Once
+}
+
+// Bus is an in-process publish/subscribe hub keeping a bounded history for clients resuming a stream
+type Bus struct {
+	mu          sync.Mutex
+	lastID      uint64
+	history     []Event
+	historySize int
+	bufferSize  int
+	subscribers map[string]map[*Subscription]struct{}
+}
+
+// Default is the bus the handlers publish Q&A activity to
+var Default = NewBus(config.EventHistorySize, config.EventBufferSize)
+
+// NewBus creates a bus remembering the given number of events and buffering as many per subscriber.
+// Event IDs start from the current time so they keep increasing across restarts.
+func NewBus(historySize, bufferSize int) *Bus {
+	return &Bus{
+		lastID:      uint64(time.Now().UnixMilli()) * 1000,
+		historySize: historySize,
+		bufferSize:  bufferSize,
+		subscribers: make(map[string]map[*Subscription]struct{}),
+	}
+}
+
+// SkillTopic is the topic of the activity within a skill
+func SkillTopic(skillID string) string {
+	return fmt.Sprintf("skill:%s", skillID)
+}
+
+// UserTopic is the topic of the activity addressed to a user
+func UserTopic(userID string) string {
+	return fmt.Sprintf("user:%s", userID)
+}
+
+// Publish delivers the event to the current subscribers of the topic and records it in the history.
+// Subscribers too slow to keep up are closed, leaving them to resume from their last event.
+func (b *Bus) Publish(topic, eventType string, data any) Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lastID++
+	event := Event{
+		ID:        b.lastID,
+		Topic:     topic,
+		Type:      eventType,
+		Data:      data,
+		CreatedAt: time.Now(),
+	}
+
+	b.history = append(b.history, event)
+	if len(b.history) > b.historySize {
+		b.history = b.history[len(b.history)-b.historySize:]
+	}
+
+	for sub := range b.subscribers[topic] {
+		select {
+		case sub.events <- event:
+		default:
+			b.remove(sub)
+		}
+	}
+
+	return event
+}
+
+// Subscribe listens to the topic, first replaying the remembered events published after lastEventID.
+// A zero lastEventID starts from the events published from now on.
+func (b *Bus) Subscribe(topic string, lastEventID uint64) *Subscription {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	missed := make([]Event, 0)
+	if lastEventID > 0 {
+		for _, event := range b.history {
+			if event.ID > lastEventID && event.Topic == topic {
+				missed = append(missed, event)
+			}
+		}
+	}
+
+	events := make(chan Event, b.bufferSize+len(missed))
+	for _, event := range missed {
+		events <- event
+	}
+
+	sub := &Subscription{
+		Events: events,
+		topic:  topic,
+		events: events,
+		bus:    b,
+	}
+
+	if b.subscribers[topic] == nil {
+		b.subscribers[topic] = make(map[*Subscription]struct{})
+	}
+	b.subscribers[topic][sub] = struct{}{}
+
+	return sub
+}
+
+// Close stops the subscription and closes its events channel
+func (s *Subscription) Close() {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+
+	s.bus.remove(s)
+}
+
+// remove unregisters the subscription, the bus lock must be held
+func (b *Bus) remove(sub *Subscription) {
+	sub.once.Do(func() {
+		delete(b.subscribers[sub.topic], sub)
+		if len(b.subscribers[sub.topic]) == 0 {
+			delete(b.subscribers, sub.topic)
+		}
+
+		close(sub.events)
+	})
+}
@@ -0,0 +1,114 @@
+package linkcheck
+
+import (
+	"career-compass-go/config"
+	"career-compass-go/pkg/logging"
+	"career-compass-go/service"
+	"career-compass-go/utils"
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"io"
+	"net/http"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// Start periodically checks every resource URL until the process exits
+func Start() {
+	client := &http.Client{Timeout: config.LinkCheckTimeout}
+
+	for {
+		Run(client)
+		time.Sleep(config.LinkCheckInterval)
+	}
+}
+
+// Run checks every resource URL once with bounded concurrency and records the outcomes
+func Run(client *http.Client) {
+	var (
+		resource service.Resource
+		wg       sync.WaitGroup
+	)
+
+	semaphore := make(chan struct{}, max(config.LinkCheckConcurrency, 1))
+	startTime := time.Now()
+	checked := 0
+
+	err := resource.GetLinks(func(resourceID primitive.ObjectID, url string) {
+		semaphore <- struct{}{}
+		wg.Add(1)
+		checked++
+
+		go func() {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+
+			check := CheckURL(client, url)
+
+			err := resource.RecordLinkCheck(resourceID, check)
+			if err != nil {
+				logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error recording link check of resource [%s] -> %s", resourceID.Hex(), err.Error()))
+			}
+		}()
+	})
+
+	wg.Wait()
+
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error iterating resource links -> %s", err.Error()))
+		return
+	}
+
+	logging.Logger.Info(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Checked %d resource links in %s", checked, time.Since(startTime)))
+}
+
+// CheckURL checks if the URL is reachable, falling back to GET for servers that reject HEAD requests
+func CheckURL(client *http.Client, url string) service.LinkCheck {
+	check := service.LinkCheck{CheckedAt: time.Now()}
+
+	statusCode, err := request(client, http.MethodHead, url)
+	if err != nil || statusCode == http.StatusMethodNotAllowed || statusCode == http.StatusForbidden || statusCode == http.StatusNotImplemented {
+		statusCode, err = request(client, http.MethodGet, url)
+	}
+
+	check.LatencyMs = time.Since(check.CheckedAt).Milliseconds()
+
+	if err != nil {
+		check.Error = err.Error()
+		check.Failed = true
+		return check
+	}
+
+	check.StatusCode = statusCode
+	// Rate limited responses say nothing about the link itself
+	check.Failed = statusCode >= http.StatusBadRequest && statusCode != http.StatusTooManyRequests
+
+	return check
+}
+
+// request makes a request with the given method and returns the response status code
+func request(client *http.Client, method, url string) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), config.LinkCheckTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("User-Agent", "CareerCompass-LinkChecker/1.0")
+
+	res, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+
+	_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, config.LinkCheckBodyLimit))
+
+	return res.StatusCode, nil
+}
@@ -0,0 +1,88 @@
+package linkcheck
+
+import (
+	"career-compass-go/config"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCheckURL(t *testing.T) {
+	config.LinkCheckTimeout = 100 * time.Millisecond
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ok", func(w http.ResponseWriter, r *http.Request) {})
+	mux.HandleFunc("/no-head", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+	mux.HandleFunc("/forbidden-head", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusForbidden)
+		}
+	})
+	mux.HandleFunc("/moved", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/ok", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/moved-gone", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/gone", http.StatusFound)
+	})
+	mux.HandleFunc("/gone", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	mux.HandleFunc("/error", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	mux.HandleFunc("/rate-limited", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+	mux.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(300 * time.Millisecond)
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	tests := []struct {
+		path       string
+		statusCode int
+		failed     bool
+		hasError   bool
+	}{
+		{"/ok", http.StatusOK, false, false},
+		{"/no-head", http.StatusOK, false, false},
+		{"/forbidden-head", http.StatusOK, false, false},
+		{"/moved", http.StatusOK, false, false},
+		{"/moved-gone", http.StatusNotFound, true, false},
+		{"/gone", http.StatusNotFound, true, false},
+		{"/error", http.StatusInternalServerError, true, false},
+		{"/rate-limited", http.StatusTooManyRequests, false, false},
+		{"/slow", 0, true, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			check := CheckURL(server.Client(), server.URL+test.path)
+
+			if check.StatusCode != test.statusCode || check.Failed != test.failed || (check.Error != "") != test.hasError {
+				t.Errorf("CheckURL(%s) = status %d, failed %v, error %q, want status %d, failed %v, error %v",
+					test.path, check.StatusCode, check.Failed, check.Error, test.statusCode, test.failed, test.hasError)
+			}
+		})
+	}
+}
+
+func TestCheckURLUnreachable(t *testing.T) {
+	config.LinkCheckTimeout = 100 * time.Millisecond
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := server.URL
+	server.Close()
+
+	check := CheckURL(http.DefaultClient, url)
+	if !check.Failed || check.Error == "" {
+		t.Errorf("CheckURL(closed server) = failed %v, error %q, want a failed check with an error", check.Failed, check.Error)
+	}
+}
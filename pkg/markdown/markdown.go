@@ -0,0 +1,185 @@
+package markdown
+
+import (
+	"bytes"
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var (
+	renderer = goldmark.New(
+		goldmark.WithExtensions(extension.GFM),
+		goldmark.WithParserOptions(
+			parser.WithInlineParsers(util.Prioritized(&mentionParser{}, 500)),
+			parser.WithASTTransformers(util.Prioritized(&mentionTransformer{}, 500)),
+		),
+	)
+
+	policy = newPolicy()
+
+	mentionPattern = regexp.MustCompile(`^@([A-Za-z0-9_](?:[A-Za-z0-9_.-]{0,30}[A-Za-z0-9_])?)`)
+
+	mentionKey = parser.NewContextKey()
+)
+
+// mentionState holds the mentioned names found while parsing and the links to render them with
+type mentionState struct {
+	links map[string]string
+	names []string
+	seen  map[string]struct{}
+}
+
+// mention is the node of a parsed @username, resolved into a link or plain text once the whole document is parsed
+type mention struct {
+	ast.BaseInline
+	name    string
+	segment text.Segment
+}
+
+var kindMention = ast.NewNodeKind("Mention")
+
+func (m *mention) Kind() ast.NodeKind {
+	return kindMention
+}
+
+func (m *mention) Dump(source []byte, level int) {
+	ast.DumpHelper(m, source, level, map[string]string{"Name": m.name}, nil)
+}
+
+// mentionParser parses @username mentions outside code
+type mentionParser struct{}
+
+func (m *mentionParser) Trigger() []byte {
+	return []byte{'@'}
+}
+
+func (m *mentionParser) Parse(parent ast.Node, block text.Reader, pc parser.Context) ast.Node {
+	// Skip e-mail addresses and other words containing an @
+	prev := block.PrecendingCharacter()
+	if unicode.IsLetter(prev) || unicode.IsDigit(prev) || prev == '_' {
+		return nil
+	}
+
+	line, segment := block.PeekLine()
+	match := mentionPattern.FindSubmatch(line)
+	if match == nil {
+		return nil
+	}
+
+	block.Advance(len(match[0]))
+
+	return &mention{name: string(match[1]), segment: text.NewSegment(segment.Start, segment.Start+len(match[0]))}
+}
+
+// mentionTransformer records the mentions and links those present in the context's links. Mentions inside a link
+// stay plain text, as a link cannot hold another link
+type mentionTransformer struct{}
+
+func (m *mentionTransformer) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
+	state, ok := pc.Get(mentionKey).(*mentionState)
+	if !ok {
+		return
+	}
+
+	mentions := make([]*mention, 0)
+
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if node, ok := n.(*mention); ok && entering {
+			mentions = append(mentions, node)
+		}
+
+		return ast.WalkContinue, nil
+	})
+
+	for _, node := range mentions {
+		replacement := ast.Node(ast.NewTextSegment(node.segment))
+
+		if !insideLink(node) {
+			key := strings.ToLower(node.name)
+			if _, ok := state.seen[key]; !ok {
+				state.seen[key] = struct{}{}
+				state.names = append(state.names, node.name)
+			}
+
+			if href, ok := state.links[key]; ok {
+				link := ast.NewLink()
+				link.Destination = []byte(href)
+				link.SetAttributeString("class", []byte("mention"))
+				link.AppendChild(link, replacement)
+
+				replacement = link
+			}
+		}
+
+		node.Parent().ReplaceChild(node.Parent(), node, replacement)
+	}
+}
+
+// insideLink reports whether the node is part of the text of a link or image
+func insideLink(n ast.Node) bool {
+	for p := n.Parent(); p != nil; p = p.Parent() {
+		switch p.(type) {
+		case *ast.Link, *ast.Image, *ast.AutoLink:
+			return true
+		}
+	}
+
+	return false
+}
+
+// newPolicy creates the sanitisation policy for user generated HTML, keeping the code block languages for highlighting
+func newPolicy() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w+#-]+$`)).OnElements("code")
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^mention$`)).OnElements("a")
+	p.RequireNoFollowOnLinks(true)
+	p.AddTargetBlankToFullyQualifiedLinks(true)
+
+	return p
+}
+
+// newContext creates the parser context tracking the mentions of a single document
+func newContext(links map[string]string) (parser.Context, *mentionState) {
+	state := &mentionState{links: links, seen: map[string]struct{}{}}
+
+	pc := parser.NewContext()
+	pc.Set(mentionKey, state)
+
+	return pc, state
+}
+
+// Mentions returns the distinct @usernames mentioned in the Markdown source, ignoring code
+func Mentions(source string) []string {
+	pc, state := newContext(nil)
+	renderer.Parser().Parse(text.NewReader([]byte(source)), parser.WithContext(pc))
+
+	return state.names
+}
+
+// Render converts the Markdown source into sanitised HTML
+func Render(source string) (string, error) {
+	return RenderWithMentions(source, nil)
+}
+
+// RenderWithMentions converts the Markdown source into sanitised HTML, linking the @usernames found in links
+// keyed by lowercase username
+func RenderWithMentions(source string, links map[string]string) (string, error) {
+	var html bytes.Buffer
+
+	pc, _ := newContext(links)
+
+	err := renderer.Convert([]byte(source), &html, parser.WithContext(pc))
+	if err != nil {
+		return "", err
+	}
+
+	return policy.Sanitize(html.String()), nil
+}
@@ -0,0 +1,138 @@
+package markdown
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		contains []string
+		excludes []string
+	}{
+		{"script", "<script>alert(1)</script>", nil, []string{"<script", "alert(1)"}},
+		{"inline script", "hi <script>alert(1)</script>", []string{"hi"}, []string{"<script"}},
+		{"onerror", "hi <img src=x onerror=alert(1)>", []string{"hi"}, []string{"onerror", "<img"}},
+		{"javascript link", "[x](javascript:alert(1))", []string{"x"}, []string{"javascript:", "href"}},
+		{"javascript anchor", `<a href="javascript:alert(1)">x</a>`, []string{"x"}, []string{"javascript:", "href"}},
+		{"code language", "```go\nfmt.Println()\n```", []string{`<code class="language-go">`}, nil},
+		{"external link", "[site](https://example.com)", []string{`href="https://example.com"`, `rel="nofollow noopener"`, `target="_blank"`}, nil},
+		{"relative link", "[profile](/users/1)", []string{`href="/users/1"`, `rel="nofollow"`}, []string{"target"}},
+		{"emphasis", "**bold** and _italic_", []string{"<strong>bold</strong>", "<em>italic</em>"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Render(tt.source)
+			if err != nil {
+				t.Fatalf("Render(%q) error = %v", tt.source, err)
+			}
+
+			for _, want := range tt.contains {
+				if !strings.Contains(got, want) {
+					t.Errorf("Render(%q) = %q, want it to contain %q", tt.source, got, want)
+				}
+			}
+
+			for _, unwanted := range tt.excludes {
+				if strings.Contains(got, unwanted) {
+					t.Errorf("Render(%q) = %q, want it without %q", tt.source, got, unwanted)
+				}
+			}
+		})
+	}
+}
+
+func TestPolicyClasses(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{"code language", `<code class="language-go">x</code>`, `<code class="language-go">x</code>`},
+		{"code language with symbols", `<code class="language-c++">x</code>`, `<code class="language-c++">x</code>`},
+		{"code other class", `<code class="evil">x</code>`, `<code>x</code>`},
+		{"code language with space", `<code class="language-go evil">x</code>`, `<code>x</code>`},
+		{"language on paragraph", `<p class="language-go">x</p>`, `<p>x</p>`},
+		{"language on span", `<span class="language-go">x</span>`, `<span>x</span>`},
+		{"mention on anchor", `<a href="/users/1" class="mention">x</a>`, `<a href="/users/1" class="mention" rel="nofollow">x</a>`},
+		{"other class on anchor", `<a href="/users/1" class="evil">x</a>`, `<a href="/users/1" rel="nofollow">x</a>`},
+		{"mention on span", `<span class="mention">x</span>`, `<span>x</span>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policy.Sanitize(tt.html); got != tt.want {
+				t.Errorf("Sanitize(%q) = %q, want %q", tt.html, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderWithMentions(t *testing.T) {
+	links := map[string]string{"bob": "/users/1", "carol_1": "/users/2"}
+	mention := `<a href="/users/1" class="mention" rel="nofollow">`
+
+	tests := []struct {
+		name     string
+		source   string
+		contains []string
+		excludes []string
+	}{
+		{"mention", "thanks @bob", []string{mention + "@bob</a>"}, nil},
+		{"case insensitive", "thanks @Bob.", []string{mention + "@Bob</a>."}, nil},
+		{"underscore", "ask @carol_1", []string{`<a href="/users/2" class="mention" rel="nofollow">@carol_1</a>`}, nil},
+		{"unknown user", "ask @alice", []string{"ask @alice"}, []string{"<a"}},
+		{"email", "mail bob@example.com", []string{"bob@example.com"}, []string{"mention"}},
+		{"code span", "run `@bob` now", []string{"<code>@bob</code>"}, []string{"mention"}},
+		{"code block", "```\n@bob\n```", []string{"@bob"}, []string{"mention"}},
+		{"inside link", "[@bob](https://example.com)", []string{"https://example.com"}, []string{"/users/1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenderWithMentions(tt.source, links)
+			if err != nil {
+				t.Fatalf("RenderWithMentions(%q) error = %v", tt.source, err)
+			}
+
+			for _, want := range tt.contains {
+				if !strings.Contains(got, want) {
+					t.Errorf("RenderWithMentions(%q) = %q, want it to contain %q", tt.source, got, want)
+				}
+			}
+
+			for _, unwanted := range tt.excludes {
+				if strings.Contains(got, unwanted) {
+					t.Errorf("RenderWithMentions(%q) = %q, want it without %q", tt.source, got, unwanted)
+				}
+			}
+		})
+	}
+}
+
+func TestMentions(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   []string
+	}{
+		{"none", "no mentions here", nil},
+		{"distinct", "@alice and @Alice and @bob", []string{"alice", "bob"}},
+		{"trailing punctuation", "thanks @alice. and @bob-", []string{"alice", "bob"}},
+		{"email", "mail alice@example.com", nil},
+		{"code span", "run `@alice`", nil},
+		{"code block", "```\n@alice\n```", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Mentions(tt.source); !slices.Equal(got, tt.want) {
+				t.Errorf("Mentions(%q) = %v, want %v", tt.source, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,243 @@
+package mlclient
+
+import (
+	"career-compass-go/config"
+	"career-compass-go/pkg/logging"
+	"career-compass-go/service"
+	"career-compass-go/utils"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"runtime"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrCircuitOpen is returned without calling the ML server while it keeps failing
+	ErrCircuitOpen = errors.New("ML server is unavailable")
+
+	// ErrTimeout is returned when the ML server does not answer within the deadline
+	ErrTimeout = errors.New("ML server timed out")
+
+	// ErrBadGateway is returned when the ML server fails or answers with an invalid response
+	ErrBadGateway = errors.New("ML server returned an invalid response")
+
+	// errUnavailable marks the failures showing the ML server is down or overloaded, worth retrying
+	errUnavailable = errors.New("ML server failed")
+)
+
+// PredictResponse holds the response of the ML server's predict API
+type PredictResponse struct {
+	Prediction   string             `json:"prediction"`
+	ModelVersion string             `json:"model_version"`
+	Candidates   []PredictCandidate `json:"candidates"`
+}
+
+// PredictCandidate holds a ranked candidate role with its probability and the contribution of every rating dimension
+type PredictCandidate struct {
+	Role          string             `json:"role"`
+	Probability   float64            `json:"probability"`
+	Contributions map[string]float64 `json:"contributions"`
+}
+
+// Options holds the timeouts, retries and circuit breaker settings of the client
+type Options struct {
+	Timeout          time.Duration
+	Retries          int
+	RetryBackoff     time.Duration
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+}
+
+// Client calls the ML server with a deadline per attempt, retrying transient failures and short-circuiting while
+// the server keeps failing
+type Client struct {
+	baseURL string
+	options Options
+	breaker breaker
+}
+
+// New creates a client for the ML server at the given base URL
+func New(baseURL string, options Options) *Client {
+	return &Client{baseURL: baseURL, options: options}
+}
+
+// NewFromConfig creates a client for the configured ML server
+func NewFromConfig() *Client {
+	return New(config.MLServerURL, Options{
+		Timeout:          config.MLTimeout,
+		Retries:          config.MLRetries,
+		RetryBackoff:     config.MLRetryBackoff,
+		BreakerThreshold: config.MLBreakerThreshold,
+		BreakerCooldown:  config.MLBreakerCooldown,
+	})
+}
+
+// Predict predicts the role for the assessment ratings, asking for the top ranked candidate roles
+func (cl *Client) Predict(ctx context.Context, ratings service.RatingsData, top int) (*PredictResponse, error) {
+	var predictResp PredictResponse
+
+	err := cl.post(ctx, fmt.Sprintf("/predict?top_n=%d", top), ratings, &predictResp)
+	if err != nil {
+		return nil, err
+	}
+
+	if predictResp.Prediction == "" {
+		return nil, fmt.Errorf("%w -> missing prediction", ErrBadGateway)
+	}
+
+	return &predictResp, nil
+}
+
+// post sends the request to the ML server path and decodes its response, retrying with jittered exponential backoff
+func (cl *Client) post(ctx context.Context, path string, reqData, respData any) error {
+	if !cl.breaker.allow(cl.options.BreakerCooldown) {
+		return ErrCircuitOpen
+	}
+
+	var err error
+
+	for attempt := 0; attempt <= cl.options.Retries; attempt++ {
+		if attempt > 0 {
+			backoff := cl.options.RetryBackoff << (attempt - 1)
+			wait := time.Duration(rand.Int63n(int64(backoff) + 1))
+
+			logging.Logger.Warning(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Retrying ML server call to %s in %s -> %s", path, wait, err.Error()))
+
+			select {
+			case <-ctx.Done():
+			case <-time.After(wait):
+			}
+		}
+
+		// Calls abandoned by the caller say nothing about the ML server
+		if ctx.Err() != nil {
+			cl.breaker.release()
+			return fmt.Errorf("%w -> %s", ErrTimeout, ctx.Err().Error())
+		}
+
+		var retry bool
+
+		retry, err = cl.attempt(ctx, path, reqData, respData)
+		if err == nil || !retry {
+			break
+		}
+	}
+
+	if err != nil && ctx.Err() != nil {
+		cl.breaker.release()
+		return fmt.Errorf("%w -> %s", ErrTimeout, ctx.Err().Error())
+	}
+
+	// Requests the ML server rejected still show it is up
+	failed := errors.Is(err, ErrTimeout) || errors.Is(err, errUnavailable)
+	cl.breaker.record(!failed, cl.options.BreakerThreshold)
+
+	if errors.Is(err, errUnavailable) {
+		return fmt.Errorf("%w -> %s", ErrBadGateway, err.Error())
+	}
+
+	return err
+}
+
+// attempt makes a single call to the ML server, reporting whether a failure is worth retrying
+func (cl *Client) attempt(ctx context.Context, path string, reqData, respData any) (bool, error) {
+	attemptCtx, cancel := context.WithTimeout(ctx, cl.options.Timeout)
+	defer cancel()
+
+	res, err := utils.RestPost(attemptCtx, cl.baseURL+path, reqData)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return ctx.Err() == nil, fmt.Errorf("%w -> %s", ErrTimeout, err.Error())
+		}
+
+		return ctx.Err() == nil, fmt.Errorf("%w -> %s", errUnavailable, err.Error())
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, config.MLResponseLimit))
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return ctx.Err() == nil, fmt.Errorf("%w -> %s", ErrTimeout, err.Error())
+		}
+
+		return true, fmt.Errorf("%w -> %s", errUnavailable, err.Error())
+	}
+
+	switch {
+	case res.StatusCode == http.StatusOK:
+	case res.StatusCode == http.StatusTooManyRequests || res.StatusCode >= http.StatusInternalServerError:
+		return true, fmt.Errorf("%w with status %d -> %s", errUnavailable, res.StatusCode, body)
+	default:
+		return false, fmt.Errorf("%w with status %d -> %s", ErrBadGateway, res.StatusCode, body)
+	}
+
+	err = json.Unmarshal(body, respData)
+	if err != nil {
+		return false, fmt.Errorf("%w -> %s", ErrBadGateway, err.Error())
+	}
+
+	return false, nil
+}
+
+// breaker opens after consecutive failed calls, letting a single trial call through once the cooldown passes
+type breaker struct {
+	mu       sync.Mutex
+	failures int
+	openedAt time.Time
+	trial    bool
+}
+
+// allow reports whether a call may go through
+func (b *breaker) allow(cooldown time.Duration) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.openedAt.IsZero() {
+		return true
+	}
+
+	if b.trial || time.Since(b.openedAt) < cooldown {
+		return false
+	}
+
+	b.trial = true
+
+	return true
+}
+
+// release lets another trial call through when the current one was abandoned
+func (b *breaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.trial = false
+}
+
+// record records the outcome of a call, opening the breaker once the failures reach the threshold
+func (b *breaker) record(success bool, threshold int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.trial = false
+
+	if success {
+		b.failures = 0
+		b.openedAt = time.Time{}
+		return
+	}
+
+	b.failures++
+	if b.failures >= threshold || !b.openedAt.IsZero() {
+		if b.openedAt.IsZero() {
+			logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Opening ML server circuit after %d failed calls", b.failures))
+		}
+
+		b.openedAt = time.Now()
+	}
+}
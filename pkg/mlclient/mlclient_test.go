@@ -0,0 +1,275 @@
+package mlclient
+
+import (
+	"career-compass-go/pkg/logging"
+	"career-compass-go/service"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMain(m *testing.M) {
+	logging.Setup()
+
+	os.Exit(m.Run())
+}
+
+// newServer serves the predict API with the handler, counting the calls it receives
+func newServer(t *testing.T, handler func(w http.ResponseWriter, call int64)) (*httptest.Server, *atomic.Int64) {
+	t.Helper()
+
+	calls := &atomic.Int64{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler(w, calls.Add(1))
+	}))
+	t.Cleanup(server.Close)
+
+	return server, calls
+}
+
+func ok(w http.ResponseWriter) {
+	_, _ = w.Write([]byte(`{"prediction": "Web Developer", "model_version": "test"}`))
+}
+
+func testOptions() Options {
+	return Options{
+		Timeout:          time.Second,
+		Retries:          2,
+		RetryBackoff:     time.Millisecond,
+		BreakerThreshold: 2,
+		BreakerCooldown:  time.Minute,
+	}
+}
+
+func TestPredictRetriesThenSucceeds(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+	}{
+		{"unavailable", http.StatusServiceUnavailable},
+		{"error", http.StatusInternalServerError},
+		{"rate-limited", http.StatusTooManyRequests},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, calls := newServer(t, func(w http.ResponseWriter, call int64) {
+				if call == 1 {
+					w.WriteHeader(tt.statusCode)
+					return
+				}
+
+				ok(w)
+			})
+
+			cl := New(server.URL, testOptions())
+
+			resp, err := cl.Predict(context.Background(), service.RatingsData{}, 3)
+			if err != nil {
+				t.Fatalf("Predict() error = %v", err)
+			}
+
+			if resp.Prediction != "Web Developer" {
+				t.Errorf("prediction = %q, want %q", resp.Prediction, "Web Developer")
+			}
+
+			if got := calls.Load(); got != 2 {
+				t.Errorf("calls = %d, want 2", got)
+			}
+		})
+	}
+}
+
+func TestPredictClientErrorNotRetried(t *testing.T) {
+	server, calls := newServer(t, func(w http.ResponseWriter, call int64) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	})
+
+	cl := New(server.URL, testOptions())
+
+	for i := 0; i < 3; i++ {
+		_, err := cl.Predict(context.Background(), service.RatingsData{}, 3)
+		if !errors.Is(err, ErrBadGateway) {
+			t.Fatalf("Predict() error = %v, want %v", err, ErrBadGateway)
+		}
+	}
+
+	// Rejected requests are neither retried nor counted against the breaker
+	if got := calls.Load(); got != 3 {
+		t.Errorf("calls = %d, want 3", got)
+	}
+}
+
+func TestBreakerOpensAfterThreshold(t *testing.T) {
+	server, calls := newServer(t, func(w http.ResponseWriter, call int64) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	opts := testOptions()
+	opts.Retries = 0
+
+	cl := New(server.URL, opts)
+
+	for i := 0; i < opts.BreakerThreshold; i++ {
+		_, err := cl.Predict(context.Background(), service.RatingsData{}, 3)
+		if !errors.Is(err, ErrBadGateway) {
+			t.Fatalf("Predict() error = %v, want %v", err, ErrBadGateway)
+		}
+	}
+
+	_, err := cl.Predict(context.Background(), service.RatingsData{}, 3)
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Predict() error = %v, want %v", err, ErrCircuitOpen)
+	}
+
+	if got := calls.Load(); got != int64(opts.BreakerThreshold) {
+		t.Errorf("calls = %d, want %d", got, opts.BreakerThreshold)
+	}
+}
+
+func TestBreakerTrialAfterCooldown(t *testing.T) {
+	var b breaker
+
+	cooldown := 20 * time.Millisecond
+
+	b.record(false, 1)
+
+	if b.allow(cooldown) {
+		t.Fatal("allow() = true during the cooldown, want false")
+	}
+
+	time.Sleep(2 * cooldown)
+
+	if !b.allow(cooldown) {
+		t.Fatal("allow() = false after the cooldown, want a trial call")
+	}
+
+	if b.allow(cooldown) {
+		t.Fatal("allow() = true while the trial call runs, want false")
+	}
+
+	// An abandoned trial lets the next call try again
+	b.release()
+
+	if !b.allow(cooldown) {
+		t.Fatal("allow() = false after the trial was released, want a trial call")
+	}
+
+	// A failed trial reopens the breaker for another cooldown
+	b.record(false, 1)
+
+	if b.allow(cooldown) {
+		t.Fatal("allow() = true after a failed trial, want false")
+	}
+
+	time.Sleep(2 * cooldown)
+
+	if !b.allow(cooldown) {
+		t.Fatal("allow() = false after the cooldown, want a trial call")
+	}
+
+	b.record(true, 1)
+
+	for i := 0; i < 2; i++ {
+		if !b.allow(cooldown) {
+			t.Fatal("allow() = false after a successful trial, want the breaker closed")
+		}
+	}
+}
+
+func TestPredictTrialCallAfterCooldown(t *testing.T) {
+	server, calls := newServer(t, func(w http.ResponseWriter, call int64) {
+		if call == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		ok(w)
+	})
+
+	opts := testOptions()
+	opts.Retries = 0
+	opts.BreakerThreshold = 1
+	opts.BreakerCooldown = 20 * time.Millisecond
+
+	cl := New(server.URL, opts)
+
+	_, err := cl.Predict(context.Background(), service.RatingsData{}, 3)
+	if !errors.Is(err, ErrBadGateway) {
+		t.Fatalf("Predict() error = %v, want %v", err, ErrBadGateway)
+	}
+
+	time.Sleep(2 * opts.BreakerCooldown)
+
+	_, err = cl.Predict(context.Background(), service.RatingsData{}, 3)
+	if err != nil {
+		t.Fatalf("trial Predict() error = %v", err)
+	}
+
+	_, err = cl.Predict(context.Background(), service.RatingsData{}, 3)
+	if err != nil {
+		t.Fatalf("Predict() after a successful trial error = %v", err)
+	}
+
+	if got := calls.Load(); got != 3 {
+		t.Errorf("calls = %d, want 3", got)
+	}
+}
+
+func TestPredictTimeout(t *testing.T) {
+	server, calls := newServer(t, func(w http.ResponseWriter, call int64) {
+		time.Sleep(200 * time.Millisecond)
+		ok(w)
+	})
+
+	opts := testOptions()
+	opts.Timeout = 20 * time.Millisecond
+	opts.Retries = 1
+
+	cl := New(server.URL, opts)
+
+	_, err := cl.Predict(context.Background(), service.RatingsData{}, 3)
+	if !errors.Is(err, ErrTimeout) {
+		t.Fatalf("Predict() error = %v, want %v", err, ErrTimeout)
+	}
+
+	// Timeouts are retried
+	if got := calls.Load(); got != 2 {
+		t.Errorf("calls = %d, want 2", got)
+	}
+}
+
+func TestPredictCancelled(t *testing.T) {
+	server, calls := newServer(t, func(w http.ResponseWriter, call int64) {
+		ok(w)
+	})
+
+	opts := testOptions()
+	opts.BreakerThreshold = 1
+
+	cl := New(server.URL, opts)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := cl.Predict(ctx, service.RatingsData{}, 3)
+	if !errors.Is(err, ErrTimeout) {
+		t.Fatalf("Predict() error = %v, want %v", err, ErrTimeout)
+	}
+
+	if got := calls.Load(); got != 0 {
+		t.Errorf("calls = %d, want 0", got)
+	}
+
+	// Calls abandoned by the caller leave the breaker closed
+	_, err = cl.Predict(context.Background(), service.RatingsData{}, 3)
+	if err != nil {
+		t.Fatalf("Predict() after a cancelled call error = %v", err)
+	}
+}
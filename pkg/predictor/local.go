@@ -0,0 +1,141 @@
+package predictor
+
+import (
+	"career-compass-go/config"
+	"career-compass-go/service"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"slices"
+	"sort"
+)
+
+// local predicts in-process with a multinomial logistic regression over the rating dimensions
+type local struct {
+	version string
+	labels  []string
+	biases  []float64
+	weights [][]float64
+}
+
+// localModel is the file format of the local model, weighting the rating dimensions by name for every role
+type localModel struct {
+	Version string `json:"version"`
+	Classes []struct {
+		Label   string             `json:"label"`
+		Bias    float64            `json:"bias"`
+		Weights map[string]float64 `json:"weights"`
+	} `json:"classes"`
+}
+
+// LoadLocal creates a predictor from the local model weights in the given JSON file
+func LoadLocal(path string) (Predictor, error) {
+	var model localModel
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(data, &model)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(model.Classes) == 0 {
+		return nil, errors.New("local model has no classes")
+	}
+
+	l := &local{version: model.Version}
+
+	for _, class := range model.Classes {
+		weights := make([]float64, len(service.RatingDimensions))
+
+		for dimension, weight := range class.Weights {
+			i := slices.Index(service.RatingDimensions, dimension)
+			if i < 0 {
+				return nil, fmt.Errorf("unknown dimension [%s] in weights of class [%s]", dimension, class.Label)
+			}
+
+			weights[i] = weight
+		}
+
+		l.labels = append(l.labels, class.Label)
+		l.biases = append(l.biases, class.Bias)
+		l.weights = append(l.weights, weights)
+	}
+
+	return l, nil
+}
+
+func (l *local) Name() string {
+	return config.LocalPredictor
+}
+
+func (l *local) Predict(_ context.Context, ratings service.RatingsData, top int) (*Prediction, error) {
+	values := ratings.Values()
+	probabilities := l.probabilities(values)
+
+	ranked := make([]int, len(l.labels))
+	for i := range ranked {
+		ranked[i] = i
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return probabilities[ranked[i]] > probabilities[ranked[j]]
+	})
+
+	prediction := &Prediction{
+		Role:         l.labels[ranked[0]],
+		ModelVersion: l.version,
+		Predictor:    l.Name(),
+		Candidates:   make([]Candidate, 0, min(top, len(ranked))),
+	}
+
+	for _, class := range ranked[:min(top, len(ranked))] {
+		// A linear model's score is the sum of every weighted rating, so each term is that rating's contribution
+		contributions := make(map[string]float64, len(values))
+		for j, value := range values {
+			contributions[service.RatingDimensions[j]] = l.weights[class][j] * float64(value)
+		}
+
+		prediction.Candidates = append(prediction.Candidates, Candidate{
+			Role:          l.labels[class],
+			Probability:   probabilities[class],
+			Contributions: newContributions(ratings, contributions),
+		})
+	}
+
+	return prediction, nil
+}
+
+// probabilities returns the softmax of the class scores for the ratings
+func (l *local) probabilities(values []int) []float64 {
+	scores := make([]float64, len(l.labels))
+	maxScore := math.Inf(-1)
+
+	for i, weights := range l.weights {
+		scores[i] = l.biases[i]
+		for j, value := range values {
+			scores[i] += weights[j] * float64(value)
+		}
+
+		maxScore = max(maxScore, scores[i])
+	}
+
+	// Shift by the highest score to keep the exponentials from overflowing
+	total := 0.0
+	for i := range scores {
+		scores[i] = math.Exp(scores[i] - maxScore)
+		total += scores[i]
+	}
+
+	for i := range scores {
+		scores[i] /= total
+	}
+
+	return scores
+}
@@ -0,0 +1,163 @@
+package predictor
+
+import (
+	"career-compass-go/config"
+	"career-compass-go/pkg/logging"
+	"career-compass-go/pkg/mlclient"
+	"career-compass-go/service"
+	"career-compass-go/utils"
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"math"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// Default is the predictor used by the API, set up from the config
+var Default Predictor
+
+// Prediction holds the role predicted for an assessment, the ranked candidate roles and the model that predicted them
+type Prediction struct {
+	Role         string      `json:"prediction"`
+	ModelVersion string      `json:"modelVersion"`
+	Predictor    string      `json:"predictor"`
+	Candidates   []Candidate `json:"candidates"`
+}
+
+// Candidate holds a candidate role with its probability and the ratings that drove it, strongest first
+type Candidate struct {
+	Role          string         `json:"role"`
+	Probability   float64        `json:"probability"`
+	Contributions []Contribution `json:"contributions"`
+}
+
+// Contribution holds how much a rating dimension pushed the prediction towards a candidate role
+type Contribution struct {
+	Dimension    string  `json:"dimension"`
+	Rating       int     `json:"rating"`
+	Contribution float64 `json:"contribution"`
+}
+
+// Predictor predicts the most suitable roles for a user's assessment ratings, ranking up to top candidates
+type Predictor interface {
+	Name() string
+	Predict(ctx context.Context, ratings service.RatingsData, top int) (*Prediction, error)
+}
+
+// Setup creates the predictor selected by the config, backed by the local model when the remote one fails
+func Setup() {
+	remote := NewRemote(mlclient.NewFromConfig())
+
+	local, err := LoadLocal(config.LocalModelPath)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error loading local model from %s -> %s", config.LocalModelPath, err.Error()))
+	} else {
+		checkRoleLabels(local)
+	}
+
+	switch {
+	case config.Predictor == config.LocalPredictor && local != nil:
+		Default = local
+	case config.PredictorFallback && local != nil:
+		Default = &fallback{primary: remote, secondary: local}
+	default:
+		Default = remote
+	}
+
+	logging.Logger.Info(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Using %s predictor", Default.Name()))
+}
+
+// checkRoleLabels reports the labels of the local model resolving to no catalog role, whose learning plans would
+// never be found. Only a server predicting with the local model alone refuses to start over them
+func checkRoleLabels(p Predictor) {
+	model, ok := p.(*local)
+	if !ok {
+		return
+	}
+
+	var role service.Role
+
+	roles, err := role.GetAll([]bson.E{}, options.Find().SetProjection(bson.D{{"name", 1}}))
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error checking the local model labels against the role catalog -> %s", err.Error()))
+		return
+	}
+
+	// A fresh database has no catalog to check the labels against yet
+	if len(roles) == 0 {
+		logging.Logger.Warning(utils.GetFrame(runtime.Caller(0)), "Role catalog is empty... Skipping the local model label check")
+		return
+	}
+
+	names := make(map[string]bool, len(roles))
+	for _, r := range roles {
+		names[strings.ToLower(r.Name)] = true
+	}
+
+	missing := make([]string, 0)
+	for _, label := range model.labels {
+		if !names[strings.ToLower(service.RoleName(label))] {
+			missing = append(missing, label)
+		}
+	}
+
+	if len(missing) == 0 {
+		return
+	}
+
+	message := fmt.Sprintf("Local model labels [%s] match no catalog role... Map them to the catalog role names in %s", strings.Join(missing, ", "), config.RoleAliasesPath)
+
+	if config.Predictor == config.LocalPredictor {
+		logging.Logger.Fatal(utils.GetFrame(runtime.Caller(0)), message)
+	}
+
+	logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), message)
+}
+
+// fallback predicts with the primary predictor, turning to the secondary one when it fails
+type fallback struct {
+	primary   Predictor
+	secondary Predictor
+}
+
+func (f *fallback) Name() string {
+	return fmt.Sprintf("%s with %s fallback", f.primary.Name(), f.secondary.Name())
+}
+
+func (f *fallback) Predict(ctx context.Context, ratings service.RatingsData, top int) (*Prediction, error) {
+	prediction, err := f.primary.Predict(ctx, ratings, top)
+	if err == nil {
+		return prediction, nil
+	}
+
+	logging.Logger.Warning(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error predicting with %s predictor, falling back to %s -> %s", f.primary.Name(), f.secondary.Name(), err.Error()))
+
+	prediction, fallbackErr := f.secondary.Predict(ctx, ratings, top)
+	if fallbackErr != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error predicting with %s predictor -> %s", f.secondary.Name(), fallbackErr.Error()))
+		return nil, err
+	}
+
+	return prediction, nil
+}
+
+// newContributions lists the non-zero contributions of the rating dimensions, strongest first
+func newContributions(ratings service.RatingsData, contributions map[string]float64) []Contribution {
+	values := ratings.Values()
+	list := make([]Contribution, 0, len(contributions))
+
+	for i, dimension := range service.RatingDimensions {
+		if contribution := contributions[dimension]; contribution != 0 {
+			list = append(list, Contribution{Dimension: dimension, Rating: values[i], Contribution: contribution})
+		}
+	}
+
+	sort.SliceStable(list, func(i, j int) bool {
+		return math.Abs(list[i].Contribution) > math.Abs(list[j].Contribution)
+	})
+
+	return list
+}
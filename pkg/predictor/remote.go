@@ -0,0 +1,52 @@
+package predictor
+
+import (
+	"career-compass-go/config"
+	"career-compass-go/pkg/mlclient"
+	"career-compass-go/service"
+	"context"
+	"sort"
+)
+
+// remote predicts with the ML server
+type remote struct {
+	client *mlclient.Client
+}
+
+// NewRemote creates a predictor calling the ML server through the given client
+func NewRemote(client *mlclient.Client) Predictor {
+	return &remote{client: client}
+}
+
+func (r *remote) Name() string {
+	return config.RemotePredictor
+}
+
+func (r *remote) Predict(ctx context.Context, ratings service.RatingsData, top int) (*Prediction, error) {
+	predictResp, err := r.client.Predict(ctx, ratings, top)
+	if err != nil {
+		return nil, err
+	}
+
+	prediction := &Prediction{
+		Role:         predictResp.Prediction,
+		ModelVersion: predictResp.ModelVersion,
+		Predictor:    r.Name(),
+		Candidates:   make([]Candidate, 0, len(predictResp.Candidates)),
+	}
+
+	sort.SliceStable(predictResp.Candidates, func(i, j int) bool {
+		return predictResp.Candidates[i].Probability > predictResp.Candidates[j].Probability
+	})
+
+	// ML server versions without ranking only return the predicted role
+	for _, candidate := range predictResp.Candidates[:min(top, len(predictResp.Candidates))] {
+		prediction.Candidates = append(prediction.Candidates, Candidate{
+			Role:          candidate.Role,
+			Probability:   candidate.Probability,
+			Contributions: newContributions(ratings, candidate.Contributions),
+		})
+	}
+
+	return prediction, nil
+}
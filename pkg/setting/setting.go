@@ -3,19 +3,42 @@ package setting
 import (
 	"career-compass-go/config"
 	"career-compass-go/pkg/logging"
+	"career-compass-go/pkg/markdown"
+	"career-compass-go/service"
 	"career-compass-go/utils"
 	"context"
+	"encoding/json"
 	"fmt"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"os"
 	"runtime"
 	"strings"
 )
 
 // Setup sets up the project dependency configurations
 func Setup() {
+	SetupMongo()
+	LoadRoleAliases()
+
+	go CreateTTLIndexForUsers()
+	go MigrateRoleSalaries()
+	go MigrateRoleCompanies()
+	go MigrateSkillResources()
+	go MigratePostContent()
+	go CreateQAIndexes()
+	go CreateReputationIndexes()
+	go CreateModerationIndexes()
+	go CreateNotificationIndexes()
+	go CreateBookmarkIndexes()
+	go CreateAssessmentIndexes()
+}
+
+// SetupMongo connects to the mongo cluster and sets up the collections
+func SetupMongo() {
 	var err error
 
 	// Connect to mongo cluster
@@ -34,8 +57,17 @@ func Setup() {
 	config.SkillCollection = config.MongoDBConn.Collection(config.ViperConfig.GetString("SKILL_COLLECTION"))
 	config.QuestionCollection = config.MongoDBConn.Collection(config.ViperConfig.GetString("QUESTION_COLLECTION"))
 	config.AnswerCollection = config.MongoDBConn.Collection(config.ViperConfig.GetString("ANSWER_COLLECTION"))
-
-	go CreateTTLIndexForUsers()
+	config.CompanyCollection = config.MongoDBConn.Collection(config.ViperConfig.GetString("COMPANY_COLLECTION"))
+	config.ResourceCollection = config.MongoDBConn.Collection(config.ViperConfig.GetString("RESOURCE_COLLECTION"))
+	config.RevisionCollection = config.MongoDBConn.Collection(config.ViperConfig.GetString("REVISION_COLLECTION"))
+	config.CommentCollection = config.MongoDBConn.Collection(config.ViperConfig.GetString("COMMENT_COLLECTION"))
+	config.ReputationCollection = config.MongoDBConn.Collection(config.ViperConfig.GetString("REPUTATION_COLLECTION"))
+	config.FlagCollection = config.MongoDBConn.Collection(config.ViperConfig.GetString("FLAG_COLLECTION"))
+	config.ModerationCollection = config.MongoDBConn.Collection(config.ViperConfig.GetString("MODERATION_COLLECTION"))
+	config.SubscriptionCollection = config.MongoDBConn.Collection(config.ViperConfig.GetString("SUBSCRIPTION_COLLECTION"))
+	config.NotificationCollection = config.MongoDBConn.Collection(config.ViperConfig.GetString("NOTIFICATION_COLLECTION"))
+	config.BookmarkCollection = config.MongoDBConn.Collection(config.ViperConfig.GetString("BOOKMARK_COLLECTION"))
+	config.AssessmentCollection = config.MongoDBConn.Collection(config.ViperConfig.GetString("ASSESSMENT_COLLECTION"))
 }
 
 // ConnectToMongo establishes a client connection to the given mongoDB URI
@@ -51,6 +83,29 @@ func ConnectToMongo(uri string) (*mongo.Client, error) {
 	return client, nil
 }
 
+// LoadRoleAliases loads the table mapping predicted role labels to the names of the catalog roles
+func LoadRoleAliases() {
+	var aliases map[string]string
+
+	config.RoleAliases = make(map[string]string)
+
+	data, err := os.ReadFile(config.RoleAliasesPath)
+	if err != nil {
+		logging.Logger.Warning(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Unable to read role aliases from %s... Matching predicted roles by name -> %s", config.RoleAliasesPath, err.Error()))
+		return
+	}
+
+	err = json.Unmarshal(data, &aliases)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing role aliases from %s -> %s", config.RoleAliasesPath, err.Error()))
+		return
+	}
+
+	for label, name := range aliases {
+		config.RoleAliases[strings.ToLower(label)] = name
+	}
+}
+
 // CloseMongoClient closes the mongo client connection
 func CloseMongoClient(client *mongo.Client) {
 	err := client.Disconnect(context.TODO())
@@ -91,3 +146,254 @@ func CreateTTLIndexForUsers() {
 		}
 	}
 }
+
+// MigrateRoleSalaries converts the legacy free-text role salaries into structured salaries
+func MigrateRoleSalaries() {
+	var role service.Role
+
+	err := role.MigrateSalaries()
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error migrating role salaries -> %s", err.Error()))
+	}
+}
+
+// MigrateRoleCompanies creates the company name index and moves the companies embedded in roles into their own collection
+func MigrateRoleCompanies() {
+	index := mongo.IndexModel{
+		Keys:    bson.D{{Key: "name", Value: 1}},
+		Options: options.Index().SetName("name_1").SetUnique(true),
+	}
+
+	_, err := config.CompanyCollection.Indexes().CreateOne(context.TODO(), index)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error creating company name index -> %s", err.Error()))
+		return
+	}
+
+	var company service.Company
+
+	err = company.MigrateEmbedded()
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error migrating role companies -> %s", err.Error()))
+	}
+}
+
+// MigrateSkillResources creates the resource ranking index and converts the legacy skill links into resources
+func MigrateSkillResources() {
+	index := mongo.IndexModel{
+		Keys:    bson.D{{Key: "skill_id", Value: 1}, {Key: "rating_average", Value: -1}},
+		Options: options.Index().SetName("skill_id_1_rating_average_-1"),
+	}
+
+	_, err := config.ResourceCollection.Indexes().CreateOne(context.TODO(), index)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error creating resource ranking index -> %s", err.Error()))
+		return
+	}
+
+	var resource service.Resource
+
+	err = resource.MigrateLegacy()
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error migrating skill resources -> %s", err.Error()))
+	}
+}
+
+// MigratePostContent renders the sanitised HTML of the questions and answers posted before Markdown support
+func MigratePostContent() {
+	for _, collection := range []*mongo.Collection{config.QuestionCollection, config.AnswerCollection} {
+		filter := bson.D{{"content_html", bson.D{{"$exists", false}}}}
+
+		cursor, err := collection.Find(context.TODO(), filter, options.Find().SetProjection(bson.D{{"content", 1}}))
+		if err != nil {
+			logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error getting %s without rendered content -> %s", collection.Name(), err.Error()))
+			continue
+		}
+
+		for cursor.Next(context.TODO()) {
+			var post struct {
+				ID      primitive.ObjectID `bson:"_id"`
+				Content string             `bson:"content"`
+			}
+
+			err = cursor.Decode(&post)
+			if err != nil {
+				logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error decoding %s content -> %s", collection.Name(), err.Error()))
+				break
+			}
+
+			contentHTML, err := markdown.Render(post.Content)
+			if err != nil {
+				logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error rendering content of [%s] -> %s", post.ID.Hex(), err.Error()))
+				continue
+			}
+
+			_, err = collection.UpdateByID(context.TODO(), post.ID, bson.D{{"$set", bson.D{{"content_html", contentHTML}}}})
+			if err != nil {
+				logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error saving rendered content of [%s] -> %s", post.ID.Hex(), err.Error()))
+			}
+		}
+
+		cursor.Close(context.TODO())
+	}
+}
+
+// CreateQAIndexes creates the indexes backing the question feed, the Q&A lookups and the leaderboards, and backfills the answer counts
+func CreateQAIndexes() {
+	indexes := map[*mongo.Collection][]bson.D{
+		config.QuestionCollection: {
+			{{Key: "skill_id", Value: 1}, {Key: "created_at", Value: -1}},
+			{{Key: "tags", Value: 1}, {Key: "created_at", Value: -1}},
+			{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}},
+			{{Key: "status", Value: 1}, {Key: "created_at", Value: -1}},
+			{{Key: "upvote", Value: -1}, {Key: "created_at", Value: -1}},
+			{{Key: "answer_count", Value: 1}, {Key: "created_at", Value: -1}},
+			{{Key: "created_at", Value: -1}},
+		},
+		config.AnswerCollection: {
+			{{Key: "question_id", Value: 1}, {Key: "accepted", Value: -1}, {Key: "score", Value: -1}},
+		},
+		config.CommentCollection: {
+			{{Key: "post_id", Value: 1}, {Key: "created_at", Value: 1}},
+			{{Key: "ancestor_ids", Value: 1}},
+		},
+		config.RevisionCollection: {
+			{{Key: "post_id", Value: 1}, {Key: "created_at", Value: -1}},
+		},
+		config.ReputationCollection: {
+			{{Key: "skill_id", Value: 1}, {Key: "reputation", Value: -1}},
+		},
+	}
+
+	for collection, keys := range indexes {
+		models := make([]mongo.IndexModel, len(keys))
+		for idx, key := range keys {
+			models[idx] = mongo.IndexModel{Keys: key}
+		}
+
+		_, err := collection.Indexes().CreateMany(context.TODO(), models)
+		if err != nil {
+			logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error creating %s indexes -> %s", collection.Name(), err.Error()))
+		}
+	}
+
+	// Questions posted before answer counts were tracked
+	cursor, err := config.QuestionCollection.Find(context.TODO(), bson.D{{"answer_count", bson.D{{"$exists", false}}}}, options.Find().SetProjection(bson.D{{"_id", 1}}))
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error getting questions without answer count -> %s", err.Error()))
+		return
+	}
+	defer cursor.Close(context.TODO())
+
+	for cursor.Next(context.TODO()) {
+		var question struct {
+			ID primitive.ObjectID `bson:"_id"`
+		}
+
+		err = cursor.Decode(&question)
+		if err != nil {
+			logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error decoding question -> %s", err.Error()))
+			return
+		}
+
+		count, err := config.AnswerCollection.CountDocuments(context.TODO(), bson.D{{"question_id", question.ID}})
+		if err != nil {
+			logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error counting answers of question [%s] -> %s", question.ID.Hex(), err.Error()))
+			continue
+		}
+
+		_, err = config.QuestionCollection.UpdateByID(context.TODO(), question.ID, bson.D{{"$set", bson.D{{"answer_count", count}}}})
+		if err != nil {
+			logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error backfilling answer count of question [%s] -> %s", question.ID.Hex(), err.Error()))
+		}
+	}
+}
+
+// CreateModerationIndexes creates the indexes backing the flag review queue and the moderation audit trail
+func CreateModerationIndexes() {
+	flagIndexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "post_id", Value: 1}, {Key: "user_id", Value: 1}},
+			Options: options.Index().SetName("post_id_1_user_id_1").SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: 1}},
+		},
+	}
+
+	_, err := config.FlagCollection.Indexes().CreateMany(context.TODO(), flagIndexes)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error creating flag indexes -> %s", err.Error()))
+	}
+
+	_, err = config.ModerationCollection.Indexes().CreateOne(context.TODO(), mongo.IndexModel{Keys: bson.D{{Key: "created_at", Value: -1}}})
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error creating moderation action index -> %s", err.Error()))
+	}
+}
+
+// CreateNotificationIndexes creates the indexes backing the subscriptions and the notification inbox
+func CreateNotificationIndexes() {
+	subscriptionIndexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "target_id", Value: 1}},
+			Options: options.Index().SetName("user_id_1_target_id_1").SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "target_id", Value: 1}},
+		},
+	}
+
+	_, err := config.SubscriptionCollection.Indexes().CreateMany(context.TODO(), subscriptionIndexes)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error creating subscription indexes -> %s", err.Error()))
+	}
+
+	index := mongo.IndexModel{
+		Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "read", Value: 1}, {Key: "created_at", Value: -1}},
+	}
+
+	_, err = config.NotificationCollection.Indexes().CreateOne(context.TODO(), index)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error creating notification index -> %s", err.Error()))
+	}
+}
+
+// CreateBookmarkIndexes creates the indexes backing the bookmark lookups, allowing a single bookmark per item and user
+func CreateBookmarkIndexes() {
+	indexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "target_id", Value: 1}},
+			Options: options.Index().SetName("user_id_1_target_id_1").SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "folder", Value: 1}, {Key: "created_at", Value: -1}},
+		},
+	}
+
+	_, err := config.BookmarkCollection.Indexes().CreateMany(context.TODO(), indexes)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error creating bookmark indexes -> %s", err.Error()))
+	}
+}
+
+// CreateAssessmentIndexes creates the index backing a user's assessment history
+func CreateAssessmentIndexes() {
+	_, err := config.AssessmentCollection.Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}},
+	})
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error creating assessment indexes -> %s", err.Error()))
+	}
+}
+
+// CreateReputationIndexes creates the unique index keeping a single reputation document per user and skill
+func CreateReputationIndexes() {
+	_, err := config.ReputationCollection.Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "skill_id", Value: 1}},
+		Options: options.Index().SetName("user_id_1_skill_id_1").SetUnique(true),
+	})
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error creating reputation indexes -> %s", err.Error()))
+	}
+}
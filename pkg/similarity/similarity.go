@@ -0,0 +1,146 @@
+package similarity
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// titleWeight repeats the title terms since titles summarise what is being asked
+const titleWeight = 2
+
+// stopWords are common words that carry no meaning about the question asked
+var stopWords = map[string]struct{}{
+	"a": {}, "an": {}, "and": {}, "are": {}, "as": {}, "at": {}, "be": {}, "but": {}, "by": {}, "can": {},
+	"do": {}, "does": {}, "for": {}, "from": {}, "has": {}, "have": {}, "how": {}, "i": {}, "if": {}, "in": {},
+	"is": {}, "it": {}, "its": {}, "me": {}, "my": {}, "of": {}, "on": {}, "or": {}, "should": {}, "so": {},
+	"that": {}, "the": {}, "there": {}, "this": {}, "to": {}, "was": {}, "what": {}, "when": {}, "where": {}, "which": {},
+	"who": {}, "why": {}, "will": {}, "with": {}, "would": {}, "you": {}, "your": {},
+}
+
+// Document is a titled text compared for similarity
+type Document struct {
+	ID      string
+	Title   string
+	Content string
+}
+
+// Match is a corpus document similar to the compared one
+type Match struct {
+	ID    string
+	Score float64
+}
+
+// Tokenize splits the text into lowercase terms, dropping stop words and single characters
+func Tokenize(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r) && r != '+' && r != '#'
+	})
+
+	terms := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if len([]rune(field)) < 2 {
+			continue
+		}
+
+		if _, ok := stopWords[field]; ok {
+			continue
+		}
+
+		terms = append(terms, field)
+	}
+
+	return terms
+}
+
+// terms tokenizes the title and content of the document, weighting the title terms
+func (d Document) terms() []string {
+	terms := make([]string, 0)
+
+	titleTerms := Tokenize(d.Title)
+	for i := 0; i < titleWeight; i++ {
+		terms = append(terms, titleTerms...)
+	}
+
+	return append(terms, Tokenize(d.Content)...)
+}
+
+// Rank scores every corpus document against the query by the cosine of their TF-IDF vectors
+// and returns those scoring at least the threshold, most similar first
+func Rank(query Document, corpus []Document, threshold float64, limit int) []Match {
+	docs := make([][]string, len(corpus)+1)
+	docs[0] = query.terms()
+	for idx, doc := range corpus {
+		docs[idx+1] = doc.terms()
+	}
+
+	// Document frequency of each term across the corpus and the query
+	df := make(map[string]int)
+	for _, terms := range docs {
+		seen := make(map[string]struct{})
+		for _, term := range terms {
+			if _, ok := seen[term]; ok {
+				continue
+			}
+
+			seen[term] = struct{}{}
+			df[term]++
+		}
+	}
+
+	total := float64(len(docs))
+	vectorize := func(terms []string) map[string]float64 {
+		vector := make(map[string]float64)
+		for _, term := range terms {
+			vector[term]++
+		}
+
+		for term, tf := range vector {
+			// Smoothed IDF keeps the terms shared by every document from weighing zero
+			vector[term] = (1 + math.Log(tf)) * (math.Log((1+total)/(1+float64(df[term]))) + 1)
+		}
+
+		return vector
+	}
+
+	queryVector := vectorize(docs[0])
+
+	matches := make([]Match, 0)
+	for idx, doc := range corpus {
+		score := cosine(queryVector, vectorize(docs[idx+1]))
+		if score >= threshold {
+			matches = append(matches, Match{ID: doc.ID, Score: score})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Score > matches[j].Score
+	})
+
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+
+	return matches
+}
+
+// cosine computes the cosine similarity of two sparse vectors
+func cosine(a, b map[string]float64) float64 {
+	var dot, normA, normB float64
+
+	for term, weight := range a {
+		normA += weight * weight
+		dot += weight * b[term]
+	}
+
+	for _, weight := range b {
+		normB += weight * weight
+	}
+
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
@@ -25,28 +25,98 @@ func SetupRouter() *gin.Engine {
 
 	router.POST("/signin", handlers.Login)
 
+	router.GET("/digest/unsubscribe", handlers.ConfirmUnsubscribeDigest)
+	router.POST("/digest/unsubscribe", handlers.UnsubscribeDigest)
+
 	// Routes that require token verification
 	authRouter := router.Group("/")
 	authRouter.Use(middlewares.VerifyToken())
 
+	authRouter.GET("/me", handlers.GetMyProfile)
+	authRouter.GET("/me/subscriptions", handlers.GetSubscriptions)
+	authRouter.GET("/me/notifications", handlers.GetNotifications)
+	authRouter.GET("/me/notifications/count", handlers.GetNotificationCounts)
+	authRouter.GET("/me/events", handlers.StreamUserEvents)
+	authRouter.GET("/me/digest", handlers.GetDigestPreference)
+	authRouter.GET("/me/bookmarks", handlers.GetBookmarks)
+	authRouter.GET("/me/bookmarks/folders", handlers.GetBookmarkFolders)
+	authRouter.GET("/me/assessments", handlers.GetAssessments)
+	authRouter.GET("/me/assessments/trend", handlers.GetAssessmentTrend)
+	authRouter.PUT("/me/digest", handlers.UpdateDigestPreference)
+	authRouter.PUT("/me/notifications/read", handlers.MarkNotificationsRead)
+	authRouter.PUT("/me/notifications/unread", handlers.MarkNotificationsUnread)
+	authRouter.GET("/:id/user", handlers.GetUserProfile)
+
 	router.POST("/role", handlers.CreateRole)
 	authRouter.GET("/role", handlers.GetAllRoles)
 	authRouter.GET("/:id/role", handlers.GetRole)
+	authRouter.GET("/roles/compare", handlers.CompareRoles)
 
 	router.POST("/skill", handlers.CreateSkill)
 	authRouter.GET("/skill", handlers.GetAllSkills)
 	authRouter.GET("/:id/skill", handlers.GetSkill)
+	authRouter.GET("/:id/skill/leaderboard", handlers.GetSkillLeaderboard)
+	authRouter.POST("/:id/skill/follow", handlers.FollowSkill)
+	authRouter.DELETE("/:id/skill/follow", handlers.UnfollowSkill)
+	authRouter.GET("/:id/skill/events", handlers.StreamSkillEvents)
+	authRouter.GET("/:id/skill/resources", handlers.GetResources)
+	authRouter.POST("/:id/skill/resources", handlers.AddResource)
+	authRouter.PUT("/:id/skill/resources/:resourceID", handlers.UpdateResource)
+	authRouter.DELETE("/:id/skill/resources/:resourceID", handlers.DeleteResource)
+	authRouter.PUT("/:id/skill/resources/:resourceID/rating", handlers.RateResource)
+	authRouter.GET("/resources/broken", handlers.GetBrokenResources)
+
+	router.POST("/company", handlers.CreateCompany)
+	authRouter.GET("/company", handlers.GetAllCompanies)
+	authRouter.GET("/:id/company", handlers.GetCompany)
+	authRouter.PUT("/:id/company", handlers.UpdateCompany)
+	authRouter.DELETE("/:id/company", handlers.DeleteCompany)
 
 	authRouter.GET("/search", handlers.Search)
 
 	authRouter.POST("/question", handlers.AddQuestion)
+	authRouter.GET("/questions", handlers.GetQuestionFeed)
+	authRouter.GET("/tags", handlers.GetTags)
 	authRouter.GET("/:id/question", handlers.GetQuestions)
 	authRouter.PUT("/:id/question", handlers.UpdateQuestion)
+	authRouter.PUT("/:id/question/content", handlers.EditQuestion)
+	authRouter.DELETE("/:id/question", handlers.DeleteQuestion)
+	authRouter.GET("/:id/question/revisions", handlers.GetQuestionRevisions)
+	authRouter.POST("/:id/question/upvote", handlers.UpvoteQuestion)
+	authRouter.DELETE("/:id/question/upvote", handlers.RemoveQuestionUpvote)
+	authRouter.POST("/:id/question/follow", handlers.FollowQuestion)
+	authRouter.DELETE("/:id/question/follow", handlers.UnfollowQuestion)
 
 	authRouter.POST("/answer", handlers.AddAnswer)
+	authRouter.PUT("/:id/answer", handlers.EditAnswer)
+	authRouter.DELETE("/:id/answer", handlers.DeleteAnswer)
+	authRouter.GET("/:id/answer/revisions", handlers.GetAnswerRevisions)
+	authRouter.POST("/preview", handlers.PreviewContent)
+
+	authRouter.POST("/comment", handlers.AddComment)
+	authRouter.GET("/:id/comment", handlers.GetComments)
+	authRouter.PUT("/:id/comment", handlers.EditComment)
+	authRouter.DELETE("/:id/comment", handlers.DeleteComment)
+	authRouter.POST("/:id/answer/upvote", handlers.UpvoteAnswer)
+	authRouter.POST("/:id/answer/downvote", handlers.DownvoteAnswer)
+	authRouter.DELETE("/:id/answer/vote", handlers.RemoveAnswerVote)
+	authRouter.PUT("/:id/answer/accept", handlers.AcceptAnswer)
+	authRouter.DELETE("/:id/answer/accept", handlers.UnacceptAnswer)
+
+	authRouter.POST("/:id/flag", handlers.FlagPost)
+	authRouter.GET("/moderation/queue", handlers.GetModerationQueue)
+	authRouter.GET("/moderation/actions", handlers.GetModerationActions)
+	authRouter.POST("/:id/moderation", handlers.ReviewPost)
+	authRouter.POST("/:id/question/merge", handlers.MergeQuestions)
+	authRouter.PUT("/:id/user/suspension", handlers.SuspendUser)
+	authRouter.DELETE("/:id/user/suspension", handlers.LiftSuspension)
+
+	authRouter.POST("/bookmark", handlers.AddBookmark)
+	authRouter.PUT("/:id/bookmark", handlers.MoveBookmark)
+	authRouter.DELETE("/:id/bookmark", handlers.DeleteBookmark)
 
 	// ML Routes
-	router.POST("/predict", handlers.Predict)
+	authRouter.POST("/predict", handlers.Predict)
 
 	return router
 }
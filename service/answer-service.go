@@ -8,19 +8,31 @@ import (
 	"fmt"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 	"runtime"
 	"time"
 )
 
 // Answer collection schema
 type Answer struct {
-	ID         primitive.ObjectID `json:"answerID" bson:"_id,omitempty"`
-	QuestionID primitive.ObjectID `json:"questionID" bson:"question_id" binding:"required"`
-	Content    string             `json:"content" bson:"content" binding:"required"`
-	UserID     primitive.ObjectID `json:"userID" bson:"user_id"`
-	UserName   string             `json:"userName" bson:"user_name"`
-	CreatedAt  time.Time          `json:"createdAt" bson:"created_at"`
-	UpdatedAt  time.Time          `json:"updatedAt" bson:"updated_at"`
+	ID           primitive.ObjectID   `json:"answerID" bson:"_id,omitempty"`
+	QuestionID   primitive.ObjectID   `json:"questionID" bson:"question_id" binding:"required"`
+	Content      string               `json:"content" bson:"content" binding:"required"`
+	ContentHTML  string               `json:"contentHTML" bson:"content_html"`
+	UserID       primitive.ObjectID   `json:"userID" bson:"user_id"`
+	UserName     string               `json:"userName" bson:"user_name"`
+	UpvoteBy     []primitive.ObjectID `json:"upvoteBy" bson:"upvote_by"`
+	DownvoteBy   []primitive.ObjectID `json:"downvoteBy" bson:"downvote_by"`
+	Score        int64                `json:"score" bson:"score"`
+	Accepted     bool                 `json:"accepted" bson:"accepted"`
+	CreatedAt    time.Time            `json:"createdAt" bson:"created_at"`
+	UpdatedAt    time.Time            `json:"updatedAt" bson:"updated_at"`
+	Mentions     []Mention            `json:"mentions,omitempty" bson:"mentions,omitempty"`
+	Unresolved   []string             `json:"unresolvedMentions,omitempty" bson:"unresolved_mentions,omitempty"`
+	Hidden       bool                 `json:"-" bson:"hidden,omitempty"`
+	Comments     []Comment            `json:"comments,omitempty" bson:"-"`
+	CommentCount int64                `json:"commentCount" bson:"-"`
 }
 
 // Add inserts a answer document
@@ -37,11 +49,22 @@ func (an *Answer) Add() error {
 	return nil
 }
 
+// Get gets the answer document based on the given filter
+func (an *Answer) Get(filters []bson.E) error {
+	err := config.AnswerCollection.FindOne(context.TODO(), bson.D(filters)).Decode(an)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error getting answer document -> %s", err.Error()))
+		return err
+	}
+
+	return nil
+}
+
 // GetAll gets the answer documents
-func (an *Answer) GetAll(filters []bson.E) ([]Answer, error) {
+func (an *Answer) GetAll(filters []bson.E, opts ...*options.FindOptions) ([]Answer, error) {
 	answers := make([]Answer, 0)
 
-	cursor, err := config.AnswerCollection.Find(context.TODO(), bson.D(filters))
+	cursor, err := config.AnswerCollection.Find(context.TODO(), bson.D(filters), opts...)
 	if err != nil {
 		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error fetching answer documents -> %s", err.Error()))
 		return nil, err
@@ -55,3 +78,100 @@ func (an *Answer) GetAll(filters []bson.E) ([]Answer, error) {
 
 	return answers, nil
 }
+
+// Update updates fields of a specific answer
+func (an *Answer) Update(answerID primitive.ObjectID, update bson.D) error {
+	_, err := config.AnswerCollection.UpdateByID(context.TODO(), answerID, update)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error updating answer [%s] -> %s", answerID.Hex(), err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+// Delete removes a specific answer
+func (an *Answer) Delete(answerID primitive.ObjectID) error {
+	res, err := config.AnswerCollection.DeleteOne(context.TODO(), bson.D{{"_id", answerID}})
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error deleting answer [%s] -> %s", answerID.Hex(), err.Error()))
+		return err
+	}
+
+	if res.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
+
+// Vote atomically sets the user's vote on an answer to up, down or none and recomputes its score,
+// reporting whether the vote changed anything
+func (an *Answer) Vote(answerID, userID primitive.ObjectID, vote int) (bool, error) {
+	// votersExpr removes the user from the given voters field and adds them back if they cast that vote
+	votersExpr := func(field string, cast bool) bson.D {
+		voters := bson.D{{"$setDifference", bson.A{bson.D{{"$ifNull", bson.A{field, bson.A{}}}}, bson.A{userID}}}}
+		if cast {
+			return bson.D{{"$concatArrays", bson.A{voters, bson.A{userID}}}}
+		}
+
+		return voters
+	}
+
+	update := mongo.Pipeline{
+		{{"$set", bson.D{
+			{"upvote_by", votersExpr("$upvote_by", vote == config.VoteUp)},
+			{"downvote_by", votersExpr("$downvote_by", vote == config.VoteDown)},
+		}}},
+		{{"$set", bson.D{
+			{"score", bson.D{{"$subtract", bson.A{bson.D{{"$size", "$upvote_by"}}, bson.D{{"$size", "$downvote_by"}}}}}},
+		}}},
+	}
+
+	res, err := config.AnswerCollection.UpdateByID(context.TODO(), answerID, update)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error voting on answer [%s] -> %s", answerID.Hex(), err.Error()))
+		return false, err
+	}
+
+	if res.MatchedCount == 0 {
+		return false, mongo.ErrNoDocuments
+	}
+
+	return res.ModifiedCount > 0, nil
+}
+
+// SetAccepted marks the answer as the accepted answer of its question, clearing any earlier accepted answer, and
+// sets the question status in a single transaction. Every acceptance writes the question first, so concurrent
+// acceptances on the same question conflict and retry instead of both committing
+func (an *Answer) SetAccepted(answerID, questionID primitive.ObjectID, accepted bool, status string) error {
+	err := withTransaction(func(ctx mongo.SessionContext) error {
+		_, err := config.QuestionCollection.UpdateByID(ctx, questionID, bson.D{{"$set", bson.D{
+			{"status", status},
+			{"updated_at", time.Now()},
+		}}})
+		if err != nil {
+			return err
+		}
+
+		if accepted {
+			_, err = config.AnswerCollection.UpdateMany(
+				ctx,
+				bson.D{{"question_id", questionID}, {"_id", bson.D{{"$ne", answerID}}}, {"accepted", true}},
+				bson.D{{"$set", bson.D{{"accepted", false}}}},
+			)
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err = config.AnswerCollection.UpdateByID(ctx, answerID, bson.D{{"$set", bson.D{{"accepted", accepted}}}})
+		return err
+	})
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error updating accepted state of answer [%s] -> %s", answerID.Hex(), err.Error()))
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"career-compass-go/config"
+	"career-compass-go/pkg/logging"
+	"career-compass-go/utils"
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"runtime"
+	"slices"
+	"time"
+)
+
+// RatingDimensions holds the names of the assessment rating dimensions in the field order of RatingsData
+var RatingDimensions = []string{
+	"rate_webDev", "rate_infraAutomation", "rate_mlAlgo", "rate_uiux", "rate_blockchain", "rate_appDev",
+	"rate_cloud", "rate_testing", "rate_dataAnalytics", "rate_embedded", "rate_ai", "rate_cyber",
+	"rate_arvr", "rate_compArch", "rate_network", "rate_projectMan", "rate_game",
+}
+
+// Assessment collection schema holding a submitted assessment and the role predicted for it
+type Assessment struct {
+	ID           primitive.ObjectID `json:"assessmentID" bson:"_id,omitempty"`
+	UserID       primitive.ObjectID `json:"-" bson:"user_id"`
+	Ratings      RatingsData        `json:"ratings" bson:"ratings"`
+	Prediction   string             `json:"prediction" bson:"prediction"`
+	ModelVersion string             `json:"modelVersion" bson:"model_version"`
+	CreatedAt    time.Time          `json:"createdAt" bson:"created_at"`
+}
+
+// AssessmentQuery holds the pagination of a user's assessment history
+type AssessmentQuery struct {
+	Page  int64 `form:"page,default=1" binding:"min=1"`
+	Limit int64 `form:"limit,default=20" binding:"min=1,max=100"`
+}
+
+// AssessmentTrend holds how a user's ratings and predicted role changed across their latest assessments, oldest first
+type AssessmentTrend struct {
+	Count       int                `json:"count"`
+	Dates       []time.Time        `json:"dates"`
+	Dimensions  []DimensionTrend   `json:"dimensions"`
+	Predictions []PredictionPeriod `json:"predictions"`
+}
+
+// DimensionTrend holds the ratings of a dimension aligned with the trend dates
+type DimensionTrend struct {
+	Dimension string `json:"dimension"`
+	Values    []int  `json:"values"`
+	Change    int    `json:"change"`
+}
+
+// PredictionPeriod holds a run of consecutive assessments predicting the same role
+type PredictionPeriod struct {
+	Prediction  string    `json:"prediction"`
+	From        time.Time `json:"from"`
+	To          time.Time `json:"to"`
+	Assessments int       `json:"assessments"`
+}
+
+// Value returns the rating of the dimension, reporting whether the dimension exists
+func (rd *RatingsData) Value(dimension string) (int, bool) {
+	i := slices.Index(RatingDimensions, dimension)
+	if i < 0 {
+		return 0, false
+	}
+
+	return rd.Values()[i], true
+}
+
+// Values returns the ratings in the order of RatingDimensions
+func (rd *RatingsData) Values() []int {
+	return []int{
+		rd.RateWebDev, rd.RateInfraAutomation, rd.RateMLAlgo, rd.RateUIUX, rd.RateBlockchain, rd.RateAppDev,
+		rd.RateCloud, rd.RateTesting, rd.RateDataAnalytics, rd.RateEmbedded, rd.RateAI, rd.RateCyber,
+		rd.RateARVR, rd.RateCompArch, rd.RateNetwork, rd.RateProjectMan, rd.RateGame,
+	}
+}
+
+// Add inserts an assessment document
+func (as *Assessment) Add() error {
+	res, err := config.AssessmentCollection.InsertOne(context.TODO(), as)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error inserting new assessment document -> %s", err.Error()))
+		return err
+	}
+
+	as.ID = res.InsertedID.(primitive.ObjectID)
+	logging.Logger.Info(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Created assessmentID -> %s", as.ID.Hex()))
+
+	return nil
+}
+
+// GetAll gets the assessment documents, newest first
+func (as *Assessment) GetAll(filters []bson.E, opts ...*options.FindOptions) ([]Assessment, error) {
+	assessments := make([]Assessment, 0)
+
+	opts = append([]*options.FindOptions{options.Find().SetSort(bson.D{{"created_at", -1}})}, opts...)
+
+	cursor, err := config.AssessmentCollection.Find(context.TODO(), bson.D(filters), opts...)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error fetching assessment documents -> %s", err.Error()))
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	err = cursor.All(context.TODO(), &assessments)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error decoding assessment documents from cursor -> %s", err.Error()))
+		return nil, err
+	}
+
+	return assessments, nil
+}
+
+// Count counts the assessment documents matching the filters
+func (as *Assessment) Count(filters []bson.E) (int64, error) {
+	count, err := config.AssessmentCollection.CountDocuments(context.TODO(), bson.D(filters))
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error counting assessment documents -> %s", err.Error()))
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// GetTrend builds the trend of the user's ratings and predicted roles across their latest assessments
+func (as *Assessment) GetTrend(userID primitive.ObjectID) (*AssessmentTrend, error) {
+	assessments, err := as.GetAll([]bson.E{{"user_id", userID}}, options.Find().SetLimit(config.AssessmentTrendLimit))
+	if err != nil {
+		return nil, err
+	}
+
+	// The latest assessments come newest first, the trend runs oldest first
+	slices.Reverse(assessments)
+
+	trend := &AssessmentTrend{
+		Count:       len(assessments),
+		Dates:       make([]time.Time, 0, len(assessments)),
+		Dimensions:  make([]DimensionTrend, len(RatingDimensions)),
+		Predictions: make([]PredictionPeriod, 0),
+	}
+
+	for i, dimension := range RatingDimensions {
+		trend.Dimensions[i] = DimensionTrend{Dimension: dimension, Values: make([]int, 0, len(assessments))}
+	}
+
+	for _, assessment := range assessments {
+		trend.Dates = append(trend.Dates, assessment.CreatedAt)
+
+		for i, value := range assessment.Ratings.Values() {
+			trend.Dimensions[i].Values = append(trend.Dimensions[i].Values, value)
+		}
+
+		last := len(trend.Predictions) - 1
+		if last >= 0 && trend.Predictions[last].Prediction == assessment.Prediction {
+			trend.Predictions[last].To = assessment.CreatedAt
+			trend.Predictions[last].Assessments++
+			continue
+		}
+
+		trend.Predictions = append(trend.Predictions, PredictionPeriod{
+			Prediction:  assessment.Prediction,
+			From:        assessment.CreatedAt,
+			To:          assessment.CreatedAt,
+			Assessments: 1,
+		})
+	}
+
+	for i := range trend.Dimensions {
+		if values := trend.Dimensions[i].Values; len(values) > 0 {
+			trend.Dimensions[i].Change = values[len(values)-1] - values[0]
+		}
+	}
+
+	return trend, nil
+}
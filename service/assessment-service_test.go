@@ -0,0 +1,30 @@
+package service_test
+
+import (
+	"career-compass-go/service"
+	"testing"
+)
+
+func TestRatingsDataValue(t *testing.T) {
+	ratings := service.RatingsData{RateWebDev: 5, RateCloud: 3, RateGame: 1}
+
+	tests := []struct {
+		dimension string
+		want      int
+		ok        bool
+	}{
+		{"rate_webDev", 5, true},
+		{"rate_cloud", 3, true},
+		{"rate_game", 1, true},
+		{"rate_ai", 0, true},
+		{"", 0, false},
+		{"rate_unknown", 0, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := ratings.Value(tt.dimension)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("Value(%q) = %d, %t, want %d, %t", tt.dimension, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"career-compass-go/config"
+	"career-compass-go/pkg/logging"
+	"career-compass-go/utils"
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"runtime"
+	"time"
+)
+
+// Bookmark collection schema holding a role, skill, question or resource saved by a user into a folder
+type Bookmark struct {
+	ID         primitive.ObjectID `json:"bookmarkID" bson:"_id,omitempty"`
+	UserID     primitive.ObjectID `json:"-" bson:"user_id"`
+	TargetID   primitive.ObjectID `json:"targetID" bson:"target_id" binding:"required"`
+	TargetType string             `json:"targetType" bson:"target_type" binding:"required,oneof=role skill question resource"`
+	Folder     string             `json:"folder" bson:"folder" binding:"max=50"`
+	CreatedAt  time.Time          `json:"createdAt" bson:"created_at"`
+}
+
+// BookmarkFolder holds a bookmark folder of a user with the number of items saved in it
+type BookmarkFolder struct {
+	Name  string `json:"name" bson:"_id"`
+	Count int64  `json:"count" bson:"count"`
+}
+
+// bookmarkCollections maps the bookmarkable item types to their collections
+func bookmarkCollections() map[string]*mongo.Collection {
+	return map[string]*mongo.Collection{
+		config.BookmarkRole:     config.RoleCollection,
+		config.BookmarkSkill:    config.SkillCollection,
+		config.BookmarkQuestion: config.QuestionCollection,
+		config.BookmarkResource: config.ResourceCollection,
+	}
+}
+
+// TargetExists checks that the bookmarked item exists
+func (bm *Bookmark) TargetExists() (bool, error) {
+	count, err := bookmarkCollections()[bm.TargetType].CountDocuments(context.TODO(), bson.D{{"_id", bm.TargetID}}, options.Count().SetLimit(1))
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error checking bookmarked %s [%s] -> %s", bm.TargetType, bm.TargetID.Hex(), err.Error()))
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// Save bookmarks the item for the user, moving an existing bookmark of the item into the folder
+func (bm *Bookmark) Save() error {
+	filter := bson.D{{"user_id", bm.UserID}, {"target_id", bm.TargetID}}
+	update := bson.D{
+		{"$set", bson.D{{"folder", bm.Folder}}},
+		{"$setOnInsert", bson.D{
+			{"target_type", bm.TargetType},
+			{"created_at", time.Now()},
+		}},
+	}
+
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	err := config.BookmarkCollection.FindOneAndUpdate(context.TODO(), filter, update, opts).Decode(bm)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error saving bookmark of %s [%s] -> %s", bm.TargetType, bm.TargetID.Hex(), err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+// Move moves the user's bookmark of the item into another folder
+func (bm *Bookmark) Move(userID, targetID primitive.ObjectID, folder string) error {
+	res, err := config.BookmarkCollection.UpdateOne(
+		context.TODO(),
+		bson.D{{"user_id", userID}, {"target_id", targetID}},
+		bson.D{{"$set", bson.D{{"folder", folder}}}},
+	)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error moving bookmark of [%s] -> %s", targetID.Hex(), err.Error()))
+		return err
+	}
+
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
+
+// Delete removes the user's bookmark of the item
+func (bm *Bookmark) Delete(userID, targetID primitive.ObjectID) error {
+	res, err := config.BookmarkCollection.DeleteOne(context.TODO(), bson.D{{"user_id", userID}, {"target_id", targetID}})
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error deleting bookmark of [%s] -> %s", targetID.Hex(), err.Error()))
+		return err
+	}
+
+	if res.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
+
+// DeleteAll removes the bookmarks of the given targets
+func (bm *Bookmark) DeleteAll(targetIDs []primitive.ObjectID) error {
+	_, err := config.BookmarkCollection.DeleteMany(context.TODO(), bson.D{{"target_id", bson.D{{"$in", targetIDs}}}})
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error deleting bookmark documents -> %s", err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+// GetAll gets the bookmark documents, newest first
+func (bm *Bookmark) GetAll(filters []bson.E) ([]Bookmark, error) {
+	bookmarks := make([]Bookmark, 0)
+
+	cursor, err := config.BookmarkCollection.Find(context.TODO(), bson.D(filters), options.Find().SetSort(bson.D{{"created_at", -1}}))
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error fetching bookmark documents -> %s", err.Error()))
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	err = cursor.All(context.TODO(), &bookmarks)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error decoding bookmark documents from cursor -> %s", err.Error()))
+		return nil, err
+	}
+
+	return bookmarks, nil
+}
+
+// GetFolders gets the user's bookmark folders with their item counts
+func (bm *Bookmark) GetFolders(userID primitive.ObjectID) ([]BookmarkFolder, error) {
+	folders := make([]BookmarkFolder, 0)
+
+	pipeline := mongo.Pipeline{
+		{{"$match", bson.D{{"user_id", userID}}}},
+		{{"$group", bson.D{{"_id", "$folder"}, {"count", bson.D{{"$sum", 1}}}}}},
+		{{"$sort", bson.D{{"_id", 1}}}},
+	}
+
+	cursor, err := config.BookmarkCollection.Aggregate(context.TODO(), pipeline)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error aggregating bookmark folders -> %s", err.Error()))
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	err = cursor.All(context.TODO(), &folders)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error decoding bookmark folders from cursor -> %s", err.Error()))
+		return nil, err
+	}
+
+	return folders, nil
+}
+
+// GetBookmarkedIDs gets which of the given items the user has bookmarked
+func (bm *Bookmark) GetBookmarkedIDs(userID primitive.ObjectID, targetIDs []primitive.ObjectID) (map[primitive.ObjectID]bool, error) {
+	bookmarked := make(map[primitive.ObjectID]bool)
+
+	if len(targetIDs) == 0 {
+		return bookmarked, nil
+	}
+
+	values, err := config.BookmarkCollection.Distinct(context.TODO(), "target_id", bson.D{{"user_id", userID}, {"target_id", bson.D{{"$in", targetIDs}}}})
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error getting bookmarked items of user [%s] -> %s", userID.Hex(), err.Error()))
+		return nil, err
+	}
+
+	for _, value := range values {
+		if targetID, ok := value.(primitive.ObjectID); ok {
+			bookmarked[targetID] = true
+		}
+	}
+
+	return bookmarked, nil
+}
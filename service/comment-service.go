@@ -0,0 +1,194 @@
+package service
+
+import (
+	"career-compass-go/config"
+	"career-compass-go/pkg/logging"
+	"career-compass-go/utils"
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"runtime"
+	"time"
+)
+
+// Comment collection schema
+type Comment struct {
+	ID          primitive.ObjectID   `json:"commentID" bson:"_id,omitempty"`
+	PostID      primitive.ObjectID   `json:"postID" bson:"post_id" binding:"required"`
+	PostType    string               `json:"postType" bson:"post_type" binding:"required,oneof=question answer"`
+	ParentID    primitive.ObjectID   `json:"parentID,omitempty" bson:"parent_id,omitempty"`
+	AncestorIDs []primitive.ObjectID `json:"-" bson:"ancestor_ids"`
+	Content     string               `json:"content" bson:"content" binding:"required"`
+	UserID      primitive.ObjectID   `json:"userID" bson:"user_id"`
+	UserName    string               `json:"userName" bson:"user_name"`
+	CreatedAt   time.Time            `json:"createdAt" bson:"created_at"`
+	UpdatedAt   time.Time            `json:"updatedAt" bson:"updated_at"`
+	Hidden      bool                 `json:"-" bson:"hidden,omitempty"`
+	Replies     []Comment            `json:"replies,omitempty" bson:"-"`
+}
+
+// CommentSummary holds the comment count and the earliest comments of a post
+type CommentSummary struct {
+	PostID  primitive.ObjectID `bson:"_id"`
+	Count   int64              `bson:"count"`
+	Preview []Comment          `bson:"preview"`
+}
+
+// Add inserts a comment document
+func (cm *Comment) Add() error {
+	res, err := config.CommentCollection.InsertOne(context.TODO(), cm)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error inserting new comment document -> %s", err.Error()))
+		return err
+	}
+
+	cm.ID = res.InsertedID.(primitive.ObjectID)
+	logging.Logger.Info(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Created commentID -> %s", cm.ID.Hex()))
+
+	return nil
+}
+
+// Get gets the comment document based on the given filter
+func (cm *Comment) Get(filters []bson.E) error {
+	err := config.CommentCollection.FindOne(context.TODO(), bson.D(filters)).Decode(cm)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error getting comment document -> %s", err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+// GetAll gets the comment documents in the order they were posted
+func (cm *Comment) GetAll(filters []bson.E) ([]Comment, error) {
+	comments := make([]Comment, 0)
+
+	cursor, err := config.CommentCollection.Find(context.TODO(), bson.D(filters), options.Find().SetSort(bson.D{{"created_at", 1}}))
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error fetching comment documents -> %s", err.Error()))
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	err = cursor.All(context.TODO(), &comments)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error decoding comment documents from cursor -> %s", err.Error()))
+		return nil, err
+	}
+
+	return comments, nil
+}
+
+// Update updates fields of a specific comment
+func (cm *Comment) Update(commentID primitive.ObjectID, update bson.D) error {
+	_, err := config.CommentCollection.UpdateByID(context.TODO(), commentID, update)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error updating comment [%s] -> %s", commentID.Hex(), err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+// Delete removes a specific comment along with all the replies in its thread
+func (cm *Comment) Delete(commentID primitive.ObjectID) error {
+	filter := bson.D{{"$or", bson.A{
+		bson.D{{"_id", commentID}},
+		bson.D{{"ancestor_ids", commentID}},
+	}}}
+
+	res, err := config.CommentCollection.DeleteMany(context.TODO(), filter)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error deleting comment [%s] -> %s", commentID.Hex(), err.Error()))
+		return err
+	}
+
+	if res.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
+
+// DeleteAll removes the comment documents of the given posts
+func (cm *Comment) DeleteAll(postIDs []primitive.ObjectID) error {
+	_, err := config.CommentCollection.DeleteMany(context.TODO(), bson.D{{"post_id", bson.D{{"$in", postIDs}}}})
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error deleting comment documents -> %s", err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+// GetSummaries gets the comment count and preview of each of the given posts in a single aggregation
+func (cm *Comment) GetSummaries(postIDs []primitive.ObjectID) (map[primitive.ObjectID]CommentSummary, error) {
+	summaries := make(map[primitive.ObjectID]CommentSummary)
+
+	if len(postIDs) == 0 {
+		return summaries, nil
+	}
+
+	pipeline := mongo.Pipeline{
+		{{"$match", bson.D{{"post_id", bson.D{{"$in", postIDs}}}, {"hidden", bson.D{{"$ne", true}}}}}},
+		{{"$sort", bson.D{{"created_at", 1}}}},
+		{{"$group", bson.D{
+			{"_id", "$post_id"},
+			{"count", bson.D{{"$sum", 1}}},
+			{"preview", bson.D{{"$push", "$$ROOT"}}},
+		}}},
+		{{"$project", bson.D{
+			{"count", 1},
+			{"preview", bson.D{{"$slice", bson.A{"$preview", config.CommentPreviewSize}}}},
+		}}},
+	}
+
+	cursor, err := config.CommentCollection.Aggregate(context.TODO(), pipeline)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error aggregating comment summaries -> %s", err.Error()))
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	for cursor.Next(context.TODO()) {
+		var summary CommentSummary
+
+		err = cursor.Decode(&summary)
+		if err != nil {
+			logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error decoding comment summary -> %s", err.Error()))
+			return nil, err
+		}
+
+		summaries[summary.PostID] = summary
+	}
+
+	return summaries, cursor.Err()
+}
+
+// BuildThreads nests the replies of the given comments under their parents
+func BuildThreads(comments []Comment) []Comment {
+	children := make(map[primitive.ObjectID][]Comment)
+	roots := make([]Comment, 0)
+
+	for _, comment := range comments {
+		if comment.ParentID.IsZero() {
+			roots = append(roots, comment)
+		} else {
+			children[comment.ParentID] = append(children[comment.ParentID], comment)
+		}
+	}
+
+	var attach func(nodes []Comment) []Comment
+	attach = func(nodes []Comment) []Comment {
+		for idx := range nodes {
+			nodes[idx].Replies = attach(children[nodes[idx].ID])
+		}
+
+		return nodes
+	}
+
+	return attach(roots)
+}
@@ -0,0 +1,181 @@
+package service
+
+import (
+	"career-compass-go/config"
+	"career-compass-go/pkg/logging"
+	"career-compass-go/utils"
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"runtime"
+)
+
+// Company collection schema
+type Company struct {
+	ID          primitive.ObjectID `json:"companyID" bson:"_id,omitempty"`
+	Name        string             `json:"name" bson:"name" binding:"required"`
+	Logo        string             `json:"logo,omitempty" bson:"logo"`
+	Description string             `json:"description,omitempty" bson:"description"`
+	Link        string             `json:"link,omitempty" bson:"link" binding:"omitempty,url"`
+	Roles       []Role             `json:"roles,omitempty" bson:"-"`
+}
+
+// Create inserts a new company document
+func (co *Company) Create() error {
+	res, err := config.CompanyCollection.InsertOne(context.TODO(), co)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error inserting new company document -> %s", err.Error()))
+		return err
+	}
+
+	co.ID = res.InsertedID.(primitive.ObjectID)
+	logging.Logger.Info(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Created companyID -> %s", co.ID.Hex()))
+
+	return nil
+}
+
+// Get gets the company document based on the given filter
+func (co *Company) Get(filters []bson.E) error {
+	err := config.CompanyCollection.FindOne(context.TODO(), bson.D(filters)).Decode(co)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error getting company document -> %s", err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+// GetAll gets all the company documents
+func (co *Company) GetAll(filters []bson.E) ([]Company, error) {
+	companies := make([]Company, 0)
+
+	cursor, err := config.CompanyCollection.Find(context.TODO(), bson.D(filters))
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error getting company documents -> %s", err.Error()))
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	err = cursor.All(context.TODO(), &companies)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error decoding company documents from cursor -> %s", err.Error()))
+		return nil, err
+	}
+
+	return companies, nil
+}
+
+// Update updates fields of a specific company
+func (co *Company) Update(companyID primitive.ObjectID, update bson.D) error {
+	res, err := config.CompanyCollection.UpdateByID(context.TODO(), companyID, update)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error updating company [%s] -> %s", companyID.Hex(), err.Error()))
+		return err
+	}
+
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
+
+// Delete removes a specific company and its references from the role documents
+func (co *Company) Delete(companyID primitive.ObjectID) error {
+	res, err := config.CompanyCollection.DeleteOne(context.TODO(), bson.D{{"_id", companyID}})
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error deleting company [%s] -> %s", companyID.Hex(), err.Error()))
+		return err
+	}
+
+	if res.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	_, err = config.RoleCollection.UpdateMany(
+		context.TODO(),
+		bson.D{{"company_ids", companyID}},
+		bson.D{{"$pull", bson.D{{"company_ids", companyID}}}},
+	)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error removing company [%s] from roles -> %s", companyID.Hex(), err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+// MigrateEmbedded moves the companies embedded in the role documents into the companies collection
+func (co *Company) MigrateEmbedded() error {
+	filter := bson.D{{"companies", bson.D{{"$type", "array"}}}}
+
+	cursor, err := config.RoleCollection.Find(context.TODO(), filter, options.Find().SetProjection(bson.D{{"companies", 1}}))
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error getting role documents with embedded companies -> %s", err.Error()))
+		return err
+	}
+	defer cursor.Close(context.TODO())
+
+	for cursor.Next(context.TODO()) {
+		var legacy struct {
+			ID        primitive.ObjectID `bson:"_id"`
+			Companies []struct {
+				Name  string `bson:"name"`
+				Image string `bson:"image"`
+				Link  string `bson:"link"`
+			} `bson:"companies"`
+		}
+
+		err = cursor.Decode(&legacy)
+		if err != nil {
+			logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error decoding embedded companies -> %s", err.Error()))
+			return err
+		}
+
+		companyIDs := make([]primitive.ObjectID, 0, len(legacy.Companies))
+		for _, embedded := range legacy.Companies {
+			if embedded.Name == "" {
+				continue
+			}
+
+			// Companies are shared across roles, so reuse the document of an already migrated company
+			var company Company
+
+			err = config.CompanyCollection.FindOneAndUpdate(
+				context.TODO(),
+				bson.D{{"name", embedded.Name}},
+				bson.D{{"$setOnInsert", bson.D{
+					{"name", embedded.Name},
+					{"logo", embedded.Image},
+					{"description", ""},
+					{"link", embedded.Link},
+				}}},
+				options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+			).Decode(&company)
+			if err != nil {
+				logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error upserting company [%s] -> %s", embedded.Name, err.Error()))
+				return err
+			}
+
+			companyIDs = append(companyIDs, company.ID)
+		}
+
+		update := bson.D{
+			{"$addToSet", bson.D{{"company_ids", bson.D{{"$each", companyIDs}}}}},
+			{"$unset", bson.D{{"companies", ""}}},
+		}
+
+		_, err = config.RoleCollection.UpdateByID(context.TODO(), legacy.ID, update)
+		if err != nil {
+			logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error migrating companies of role [%s] -> %s", legacy.ID.Hex(), err.Error()))
+			return err
+		}
+
+		logging.Logger.Info(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Migrated %d companies of role [%s]", len(companyIDs), legacy.ID.Hex()))
+	}
+
+	return cursor.Err()
+}
@@ -0,0 +1,163 @@
+package service
+
+import (
+	"career-compass-go/config"
+	"career-compass-go/pkg/logging"
+	"career-compass-go/utils"
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"runtime"
+	"time"
+)
+
+// Digest holds the summary of the followed skills' questions emailed to a user
+type Digest struct {
+	Username       string
+	Frequency      string
+	Since          time.Time
+	Skills         []DigestSkill
+	UnsubscribeURL string
+}
+
+// DigestSkill holds the new and the still unanswered questions of a followed skill
+type DigestSkill struct {
+	SkillID      primitive.ObjectID
+	Name         string
+	NewQuestions []Question
+	Unanswered   []Question
+}
+
+// DigestPreference holds how often a user receives the digest of their followed skills
+type DigestPreference struct {
+	Frequency string `json:"frequency" binding:"required,oneof=none daily weekly"`
+}
+
+// GetDigestFrequency gets the user's digest frequency, weekly unless they chose otherwise
+func (us *User) GetDigestFrequency() string {
+	if us.DigestFrequency == "" {
+		return config.DigestWeekly
+	}
+
+	return us.DigestFrequency
+}
+
+// GetDigestRecipients gets the users following a skill whose digest of the given frequency was last sent before the given time
+func (us *User) GetDigestRecipients(frequency string, sentBefore time.Time) ([]User, error) {
+	users := make([]User, 0)
+
+	followerIDs, err := config.SubscriptionCollection.Distinct(context.TODO(), "user_id", bson.D{{"target_type", config.SubscriptionSkill}})
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error getting skill followers -> %s", err.Error()))
+		return nil, err
+	}
+
+	if len(followerIDs) == 0 {
+		return users, nil
+	}
+
+	frequencyFilter := bson.E{"digest_frequency", frequency}
+	if frequency == config.DigestWeekly {
+		// Users who never chose a frequency get the weekly digest
+		frequencyFilter = bson.E{"digest_frequency", bson.D{{"$in", bson.A{frequency, nil}}}}
+	}
+
+	filter := bson.D{
+		{"_id", bson.D{{"$in", followerIDs}}},
+		frequencyFilter,
+		{"$or", bson.A{
+			bson.D{{"digest_sent_at", bson.D{{"$exists", false}}}},
+			bson.D{{"digest_sent_at", bson.D{{"$lt", sentBefore}}}},
+		}},
+	}
+
+	cursor, err := config.UserCollection.Find(context.TODO(), filter, options.Find().SetProjection(bson.D{
+		{"username", 1},
+		{"email", 1},
+		{"digest_frequency", 1},
+		{"digest_sent_at", 1},
+	}))
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error getting [%s] digest recipients -> %s", frequency, err.Error()))
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	err = cursor.All(context.TODO(), &users)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error decoding digest recipients from cursor -> %s", err.Error()))
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// Build collects the questions asked since the given time and those still unanswered in each skill the user follows
+func (dg *Digest) Build(userID primitive.ObjectID, since time.Time) error {
+	var (
+		subscription Subscription
+		question     Question
+	)
+
+	subscriptions, err := subscription.GetAll([]bson.E{{"user_id", userID}, {"target_type", config.SubscriptionSkill}})
+	if err != nil {
+		return err
+	}
+
+	dg.Since = since
+	dg.Skills = make([]DigestSkill, 0, len(subscriptions))
+
+	questionOptions := options.Find().
+		SetSort(bson.D{{"created_at", -1}}).
+		SetLimit(config.DigestQuestionLimit).
+		SetProjection(bson.D{{"title", 1}, {"status", 1}, {"tags", 1}, {"answer_count", 1}, {"user_name", 1}, {"created_at", 1}})
+
+	for _, sub := range subscriptions {
+		var skill Skill
+
+		err = skill.Get([]bson.E{{"_id", sub.TargetID}})
+		if err != nil {
+			continue
+		}
+
+		newQuestions, err := question.GetAll([]bson.E{
+			{"skill_id", skill.ID},
+			{"hidden", bson.D{{"$ne", true}}},
+			{"created_at", bson.D{{"$gte", since}}},
+		}, questionOptions)
+		if err != nil {
+			return err
+		}
+
+		unanswered, err := question.GetAll([]bson.E{
+			{"skill_id", skill.ID},
+			{"hidden", bson.D{{"$ne", true}}},
+			{"status", config.QuestionUnresolved},
+			{"answer_count", 0},
+			{"created_at", bson.D{{"$lt", since}}},
+		}, questionOptions)
+		if err != nil {
+			return err
+		}
+
+		if len(newQuestions) == 0 && len(unanswered) == 0 {
+			continue
+		}
+
+		dg.Skills = append(dg.Skills, DigestSkill{
+			SkillID:      skill.ID,
+			Name:         skill.Name,
+			NewQuestions: newQuestions,
+			Unanswered:   unanswered,
+		})
+	}
+
+	return nil
+}
+
+// IsEmpty checks whether the digest has nothing to report
+func (dg *Digest) IsEmpty() bool {
+	return len(dg.Skills) == 0
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"career-compass-go/config"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"strings"
+)
+
+// LearningPlan holds the catalog role of a prediction with the skills the user still has to learn and the
+// resources to learn them
+type LearningPlan struct {
+	Role        Role       `json:"role"`
+	KnownSkills []Skill    `json:"knownSkills"`
+	SkillGap    []Skill    `json:"skillGap"`
+	Resources   []Resource `json:"resources"`
+}
+
+// RoleName returns the catalog role name of a predicted label through the alias table, the label itself otherwise
+func RoleName(label string) string {
+	if name, ok := config.RoleAliases[strings.ToLower(strings.TrimSpace(label))]; ok {
+		return name
+	}
+
+	return strings.TrimSpace(label)
+}
+
+// Build builds the user's learning plan for the predicted role label. Skills count as known when the user rated
+// their assessment dimension highly enough or earned enough reputation in them
+func (lp *LearningPlan) Build(label string, userID primitive.ObjectID, ratings RatingsData) error {
+	var (
+		role       Role
+		reputation Reputation
+		resource   Resource
+	)
+
+	err := role.GetByName(RoleName(label))
+	if err != nil {
+		return err
+	}
+
+	err = role.GetDetails(role.ID)
+	if err != nil {
+		return err
+	}
+
+	reputations, err := reputation.GetAll([]bson.E{
+		{"user_id", userID},
+		{"skill_id", bson.D{{"$in", role.SkillIDs}}},
+		{"reputation", bson.D{{"$gte", config.SkillProficiencyReputation}}},
+	})
+	if err != nil {
+		return err
+	}
+
+	known := make(map[primitive.ObjectID]bool, len(reputations))
+	for _, rp := range reputations {
+		known[rp.SkillID] = true
+	}
+
+	lp.KnownSkills = make([]Skill, 0, len(known))
+	lp.SkillGap = make([]Skill, 0, len(role.Skills))
+	gapIDs := make([]primitive.ObjectID, 0, len(role.Skills))
+
+	for _, skill := range role.Skills {
+		if rating, ok := ratings.Value(skill.Dimension); ok && rating >= config.SkillProficiencyRating {
+			known[skill.ID] = true
+		}
+
+		if known[skill.ID] {
+			lp.KnownSkills = append(lp.KnownSkills, skill)
+			continue
+		}
+
+		lp.SkillGap = append(lp.SkillGap, skill)
+		gapIDs = append(gapIDs, skill.ID)
+	}
+
+	lp.Resources = make([]Resource, 0, len(gapIDs)*config.RecommendedResourceLimit)
+
+	if len(gapIDs) > 0 {
+		resources, err := resource.GetRanked([]bson.E{
+			{"skill_id", bson.D{{"$in", gapIDs}}},
+			{"broken", bson.D{{"$ne", true}}},
+		})
+		if err != nil {
+			return err
+		}
+
+		// Recommend the best rated resources of every missing skill
+		perSkill := make(map[primitive.ObjectID]int, len(gapIDs))
+		for _, re := range resources {
+			if perSkill[re.SkillID] < config.RecommendedResourceLimit {
+				perSkill[re.SkillID]++
+				lp.Resources = append(lp.Resources, re)
+			}
+		}
+	}
+
+	role.SkillIDs = nil
+	role.CompanyIDs = nil
+	lp.Role = role
+
+	return nil
+}
@@ -0,0 +1,260 @@
+package service
+
+import (
+	"career-compass-go/config"
+	"career-compass-go/pkg/logging"
+	"career-compass-go/utils"
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"runtime"
+	"time"
+)
+
+// Flag collection schema holding a user's report of a question, answer or comment
+type Flag struct {
+	ID        primitive.ObjectID `json:"flagID" bson:"_id,omitempty"`
+	PostID    primitive.ObjectID `json:"postID" bson:"post_id"`
+	PostType  string             `json:"postType" bson:"post_type" binding:"required,oneof=question answer comment"`
+	Reason    string             `json:"reason" bson:"reason" binding:"required,max=500"`
+	UserID    primitive.ObjectID `json:"userID" bson:"user_id"`
+	Status    string             `json:"status" bson:"status"`
+	CreatedAt time.Time          `json:"createdAt" bson:"created_at"`
+}
+
+// FlagReview holds the pending flags of a post awaiting a moderator's review
+type FlagReview struct {
+	PostID         primitive.ObjectID `json:"postID" bson:"_id"`
+	PostType       string             `json:"postType" bson:"post_type"`
+	AuthorID       primitive.ObjectID `json:"authorID" bson:"author_id"`
+	Content        string             `json:"content" bson:"content"`
+	Hidden         bool               `json:"hidden" bson:"hidden"`
+	FlagCount      int64              `json:"flagCount" bson:"flag_count"`
+	Reasons        []string           `json:"reasons" bson:"reasons"`
+	FirstFlaggedAt time.Time          `json:"firstFlaggedAt" bson:"first_flagged_at"`
+	LastFlaggedAt  time.Time          `json:"lastFlaggedAt" bson:"last_flagged_at"`
+}
+
+// ModerationAction collection schema holding the audit trail of moderation
+type ModerationAction struct {
+	ID            primitive.ObjectID `json:"actionID" bson:"_id,omitempty"`
+	Action        string             `json:"action" bson:"action"`
+	PostID        primitive.ObjectID `json:"postID,omitempty" bson:"post_id,omitempty"`
+	PostType      string             `json:"postType,omitempty" bson:"post_type,omitempty"`
+	TargetUserID  primitive.ObjectID `json:"targetUserID" bson:"target_user_id"`
+	ModeratorID   primitive.ObjectID `json:"moderatorID,omitempty" bson:"moderator_id,omitempty"`
+	ModeratorName string             `json:"moderatorName,omitempty" bson:"moderator_name,omitempty"`
+	Reason        string             `json:"reason" bson:"reason"`
+	CreatedAt     time.Time          `json:"createdAt" bson:"created_at"`
+}
+
+// postCollections maps the flaggable post types to their collections
+func postCollections() map[string]*mongo.Collection {
+	return map[string]*mongo.Collection{
+		config.QuestionPost: config.QuestionCollection,
+		config.AnswerPost:   config.AnswerCollection,
+		config.CommentPost:  config.CommentCollection,
+	}
+}
+
+// GetPostAuthor gets the author of a question, answer or comment
+func GetPostAuthor(postType string, postID primitive.ObjectID) (primitive.ObjectID, error) {
+	var post struct {
+		UserID primitive.ObjectID `bson:"user_id"`
+	}
+
+	err := postCollections()[postType].FindOne(context.TODO(), bson.D{{"_id", postID}}, options.FindOne().SetProjection(bson.D{{"user_id", 1}})).Decode(&post)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error getting %s [%s] -> %s", postType, postID.Hex(), err.Error()))
+		return primitive.NilObjectID, err
+	}
+
+	return post.UserID, nil
+}
+
+// SetPostHidden hides or restores a question, answer or comment
+func SetPostHidden(postType string, postID primitive.ObjectID, hidden bool) error {
+	update := bson.D{{"$set", bson.D{{"hidden", true}}}}
+	if !hidden {
+		update = bson.D{{"$unset", bson.D{{"hidden", ""}}}}
+	}
+
+	res, err := postCollections()[postType].UpdateByID(context.TODO(), postID, update)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error updating visibility of %s [%s] -> %s", postType, postID.Hex(), err.Error()))
+		return err
+	}
+
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
+
+// HidePost hides a question, answer or comment that is not hidden yet, reporting whether this call hid it
+func HidePost(postType string, postID primitive.ObjectID) (bool, error) {
+	filter := bson.D{{"_id", postID}, {"hidden", bson.D{{"$ne", true}}}}
+
+	res, err := postCollections()[postType].UpdateOne(context.TODO(), filter, bson.D{{"$set", bson.D{{"hidden", true}}}})
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error hiding %s [%s] -> %s", postType, postID.Hex(), err.Error()))
+		return false, err
+	}
+
+	return res.ModifiedCount > 0, nil
+}
+
+// Add inserts a flag document
+func (fl *Flag) Add() error {
+	res, err := config.FlagCollection.InsertOne(context.TODO(), fl)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error inserting new flag document -> %s", err.Error()))
+		return err
+	}
+
+	fl.ID = res.InsertedID.(primitive.ObjectID)
+	logging.Logger.Info(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Created flagID -> %s", fl.ID.Hex()))
+
+	return nil
+}
+
+// CountPending counts the pending flags of a post
+func (fl *Flag) CountPending(postID primitive.ObjectID) (int64, error) {
+	count, err := config.FlagCollection.CountDocuments(context.TODO(), bson.D{{"post_id", postID}, {"status", config.FlagPending}})
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error counting flags of post [%s] -> %s", postID.Hex(), err.Error()))
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// Resolve marks the pending flags of a post as resolved
+func (fl *Flag) Resolve(postID primitive.ObjectID) error {
+	_, err := config.FlagCollection.UpdateMany(
+		context.TODO(),
+		bson.D{{"post_id", postID}, {"status", config.FlagPending}},
+		bson.D{{"$set", bson.D{{"status", config.FlagResolved}}}},
+	)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error resolving flags of post [%s] -> %s", postID.Hex(), err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+// DeleteAllPending removes the pending flags of the given posts, keeping the resolved ones as history
+func (fl *Flag) DeleteAllPending(postIDs []primitive.ObjectID) error {
+	_, err := config.FlagCollection.DeleteMany(context.TODO(), bson.D{{"post_id", bson.D{{"$in", postIDs}}}, {"status", config.FlagPending}})
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error deleting pending flag documents -> %s", err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+// GetQueue gets the posts with pending flags, the most flagged first, along with their content
+func (fl *Flag) GetQueue() ([]FlagReview, error) {
+	queue := make([]FlagReview, 0)
+
+	pipeline := mongo.Pipeline{
+		{{"$match", bson.D{{"status", config.FlagPending}}}},
+		{{"$sort", bson.D{{"created_at", 1}}}},
+		{{"$group", bson.D{
+			{"_id", "$post_id"},
+			{"post_type", bson.D{{"$first", "$post_type"}}},
+			{"flag_count", bson.D{{"$sum", 1}}},
+			{"reasons", bson.D{{"$push", "$reason"}}},
+			{"first_flagged_at", bson.D{{"$first", "$created_at"}}},
+			{"last_flagged_at", bson.D{{"$last", "$created_at"}}},
+		}}},
+		{{"$sort", bson.D{{"flag_count", -1}, {"first_flagged_at", 1}}}},
+		{{"$limit", config.ModerationPageSize}},
+	}
+
+	// Each post type lives in its own collection, so look all of them up and keep the one that matched
+	postFields := make(bson.A, 0, len(postCollections()))
+	for postType, collection := range postCollections() {
+		pipeline = append(pipeline, bson.D{{"$lookup", bson.D{
+			{"from", collection.Name()},
+			{"localField", "_id"},
+			{"foreignField", "_id"},
+			{"pipeline", mongo.Pipeline{{{"$project", bson.D{{"user_id", 1}, {"content", 1}, {"hidden", 1}}}}}},
+			{"as", postType},
+		}}})
+		postFields = append(postFields, "$"+postType)
+	}
+
+	pipeline = append(pipeline,
+		bson.D{{"$set", bson.D{{"post", bson.D{{"$first", bson.D{{"$concatArrays", postFields}}}}}}}},
+		bson.D{{"$project", bson.D{
+			{"post_type", 1},
+			{"flag_count", 1},
+			{"reasons", 1},
+			{"first_flagged_at", 1},
+			{"last_flagged_at", 1},
+			{"author_id", "$post.user_id"},
+			{"content", "$post.content"},
+			{"hidden", bson.D{{"$ifNull", bson.A{"$post.hidden", false}}}},
+		}}},
+	)
+
+	cursor, err := config.FlagCollection.Aggregate(context.TODO(), pipeline)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error aggregating flag review queue -> %s", err.Error()))
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	err = cursor.All(context.TODO(), &queue)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error decoding flag review queue from cursor -> %s", err.Error()))
+		return nil, err
+	}
+
+	return queue, nil
+}
+
+// Add inserts a moderation action document
+func (ma *ModerationAction) Add() error {
+	ma.CreatedAt = time.Now()
+
+	res, err := config.ModerationCollection.InsertOne(context.TODO(), ma)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error inserting new moderation action document -> %s", err.Error()))
+		return err
+	}
+
+	ma.ID = res.InsertedID.(primitive.ObjectID)
+	logging.Logger.Info(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Recorded moderation action [%s] on user [%s]", ma.Action, ma.TargetUserID.Hex()))
+
+	return nil
+}
+
+// GetAll gets the moderation actions, newest first
+func (ma *ModerationAction) GetAll(filters []bson.E, opts ...*options.FindOptions) ([]ModerationAction, error) {
+	actions := make([]ModerationAction, 0)
+
+	opts = append([]*options.FindOptions{options.Find().SetSort(bson.D{{"created_at", -1}})}, opts...)
+
+	cursor, err := config.ModerationCollection.Find(context.TODO(), bson.D(filters), opts...)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error fetching moderation action documents -> %s", err.Error()))
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	err = cursor.All(context.TODO(), &actions)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error decoding moderation action documents from cursor -> %s", err.Error()))
+		return nil, err
+	}
+
+	return actions, nil
+}
@@ -0,0 +1,219 @@
+package service
+
+import (
+	"career-compass-go/config"
+	"career-compass-go/pkg/logging"
+	"career-compass-go/utils"
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"runtime"
+	"time"
+)
+
+// Subscription collection schema holding a user following a question or skill
+type Subscription struct {
+	ID         primitive.ObjectID `json:"subscriptionID" bson:"_id,omitempty"`
+	UserID     primitive.ObjectID `json:"userID" bson:"user_id"`
+	TargetID   primitive.ObjectID `json:"targetID" bson:"target_id"`
+	TargetType string             `json:"targetType" bson:"target_type"`
+	CreatedAt  time.Time          `json:"createdAt" bson:"created_at"`
+}
+
+// Notification collection schema holding an in-app notification of Q&A activity
+type Notification struct {
+	ID         primitive.ObjectID `json:"notificationID" bson:"_id,omitempty"`
+	UserID     primitive.ObjectID `json:"-" bson:"user_id"`
+	Type       string             `json:"type" bson:"type"`
+	ActorID    primitive.ObjectID `json:"actorID" bson:"actor_id"`
+	ActorName  string             `json:"actorName" bson:"actor_name"`
+	SkillID    primitive.ObjectID `json:"skillID,omitempty" bson:"skill_id,omitempty"`
+	QuestionID primitive.ObjectID `json:"questionID,omitempty" bson:"question_id,omitempty"`
+	PostID     primitive.ObjectID `json:"postID,omitempty" bson:"post_id,omitempty"`
+	Message    string             `json:"message" bson:"message"`
+	Read       bool               `json:"read" bson:"read"`
+	CreatedAt  time.Time          `json:"createdAt" bson:"created_at"`
+}
+
+// NotificationQuery holds the filter and pagination of a user's notifications
+type NotificationQuery struct {
+	Unread bool  `form:"unread"`
+	Page   int64 `form:"page,default=1" binding:"min=1"`
+	Limit  int64 `form:"limit,default=20" binding:"min=1,max=100"`
+}
+
+// Add subscribes the user to the target, leaving an existing subscription untouched
+func (sb *Subscription) Add() error {
+	filter := bson.D{{"user_id", sb.UserID}, {"target_id", sb.TargetID}}
+	update := bson.D{{"$setOnInsert", bson.D{
+		{"target_type", sb.TargetType},
+		{"created_at", time.Now()},
+	}}}
+
+	_, err := config.SubscriptionCollection.UpdateOne(context.TODO(), filter, update, options.Update().SetUpsert(true))
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error subscribing user [%s] to %s [%s] -> %s", sb.UserID.Hex(), sb.TargetType, sb.TargetID.Hex(), err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+// Delete unsubscribes the user from the target
+func (sb *Subscription) Delete(userID, targetID primitive.ObjectID) error {
+	res, err := config.SubscriptionCollection.DeleteOne(context.TODO(), bson.D{{"user_id", userID}, {"target_id", targetID}})
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error unsubscribing user [%s] from [%s] -> %s", userID.Hex(), targetID.Hex(), err.Error()))
+		return err
+	}
+
+	if res.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
+
+// DeleteAll removes the subscriptions to the given targets
+func (sb *Subscription) DeleteAll(targetIDs []primitive.ObjectID) error {
+	_, err := config.SubscriptionCollection.DeleteMany(context.TODO(), bson.D{{"target_id", bson.D{{"$in", targetIDs}}}})
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error deleting subscription documents -> %s", err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+// GetAll gets the subscription documents, newest first
+func (sb *Subscription) GetAll(filters []bson.E) ([]Subscription, error) {
+	subscriptions := make([]Subscription, 0)
+
+	cursor, err := config.SubscriptionCollection.Find(context.TODO(), bson.D(filters), options.Find().SetSort(bson.D{{"created_at", -1}}))
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error fetching subscription documents -> %s", err.Error()))
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	err = cursor.All(context.TODO(), &subscriptions)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error decoding subscription documents from cursor -> %s", err.Error()))
+		return nil, err
+	}
+
+	return subscriptions, nil
+}
+
+// GetSubscriberIDs gets the distinct users subscribed to any of the targets
+func (sb *Subscription) GetSubscriberIDs(targetIDs []primitive.ObjectID) ([]primitive.ObjectID, error) {
+	values, err := config.SubscriptionCollection.Distinct(context.TODO(), "user_id", bson.D{{"target_id", bson.D{{"$in", targetIDs}}}})
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error getting subscribers -> %s", err.Error()))
+		return nil, err
+	}
+
+	userIDs := make([]primitive.ObjectID, 0, len(values))
+	for _, value := range values {
+		if userID, ok := value.(primitive.ObjectID); ok {
+			userIDs = append(userIDs, userID)
+		}
+	}
+
+	return userIDs, nil
+}
+
+// AddAll inserts a copy of the notification for each of the recipients and returns the inserted copies
+func (nt *Notification) AddAll(recipientIDs []primitive.ObjectID) ([]Notification, error) {
+	notifications := make([]Notification, len(recipientIDs))
+
+	if len(recipientIDs) == 0 {
+		return notifications, nil
+	}
+
+	nt.CreatedAt = time.Now()
+
+	docs := make([]any, len(recipientIDs))
+	for idx, recipientID := range recipientIDs {
+		notifications[idx] = *nt
+		notifications[idx].ID = primitive.NewObjectID()
+		notifications[idx].UserID = recipientID
+		docs[idx] = notifications[idx]
+	}
+
+	_, err := config.NotificationCollection.InsertMany(context.TODO(), docs)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error inserting [%s] notifications -> %s", nt.Type, err.Error()))
+		return nil, err
+	}
+
+	return notifications, nil
+}
+
+// GetAll gets the notification documents, newest first
+func (nt *Notification) GetAll(filters []bson.E, opts ...*options.FindOptions) ([]Notification, error) {
+	notifications := make([]Notification, 0)
+
+	opts = append([]*options.FindOptions{options.Find().SetSort(bson.D{{"created_at", -1}})}, opts...)
+
+	cursor, err := config.NotificationCollection.Find(context.TODO(), bson.D(filters), opts...)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error fetching notification documents -> %s", err.Error()))
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	err = cursor.All(context.TODO(), &notifications)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error decoding notification documents from cursor -> %s", err.Error()))
+		return nil, err
+	}
+
+	return notifications, nil
+}
+
+// Count counts the notification documents matching the given filter
+func (nt *Notification) Count(filters []bson.E) (int64, error) {
+	count, err := config.NotificationCollection.CountDocuments(context.TODO(), bson.D(filters))
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error counting notification documents -> %s", err.Error()))
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// SetRead marks the given notifications of the user, or all of them when none are given, as read or unread
+func (nt *Notification) SetRead(userID primitive.ObjectID, notificationIDs []primitive.ObjectID, read bool) (int64, error) {
+	filter := bson.D{{"user_id", userID}, {"read", !read}}
+	if len(notificationIDs) > 0 {
+		filter = append(filter, bson.E{"_id", bson.D{{"$in", notificationIDs}}})
+	}
+
+	res, err := config.NotificationCollection.UpdateMany(context.TODO(), filter, bson.D{{"$set", bson.D{{"read", read}}}})
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error updating notifications of user [%s] -> %s", userID.Hex(), err.Error()))
+		return 0, err
+	}
+
+	return res.ModifiedCount, nil
+}
+
+// DeleteAll removes the notifications about the given posts, or about a question among them
+func (nt *Notification) DeleteAll(postIDs []primitive.ObjectID) error {
+	filter := bson.D{{"$or", bson.A{
+		bson.D{{"post_id", bson.D{{"$in", postIDs}}}},
+		bson.D{{"question_id", bson.D{{"$in", postIDs}}}},
+	}}}
+
+	_, err := config.NotificationCollection.DeleteMany(context.TODO(), filter)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error deleting notification documents -> %s", err.Error()))
+		return err
+	}
+
+	return nil
+}
@@ -3,29 +3,70 @@ package service
 import (
 	"career-compass-go/config"
 	"career-compass-go/pkg/logging"
+	"career-compass-go/pkg/similarity"
 	"career-compass-go/utils"
 	"context"
 	"fmt"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"regexp"
 	"runtime"
 	"time"
 )
 
 // Question collection schema
 type Question struct {
-	ID        primitive.ObjectID   `json:"questionID" bson:"_id,omitempty"`
-	SkillID   primitive.ObjectID   `json:"skillID" bson:"skill_id" binding:"required"`
-	Title     string               `json:"title" bson:"title" binding:"required"`
-	Content   string               `json:"content" bson:"content" binding:"required"`
-	Status    string               `json:"status" bson:"status"`
-	UserID    primitive.ObjectID   `json:"userID" bson:"user_id"`
-	UserName  string               `json:"userName" bson:"user_name"`
-	Upvote    int64                `json:"upvote" bson:"upvote"`
-	UpvoteBy  []primitive.ObjectID `json:"upvoteBy" bson:"upvote_by"`
-	CreatedAt time.Time            `json:"createdAt" bson:"created_at"`
-	UpdatedAt time.Time            `json:"updatedAt" bson:"updated_at"`
-	Answers   []Answer             `json:"answers,omitempty" bson:"-"`
+	ID           primitive.ObjectID   `json:"questionID" bson:"_id,omitempty"`
+	SkillID      primitive.ObjectID   `json:"skillID" bson:"skill_id" binding:"required"`
+	Title        string               `json:"title" bson:"title" binding:"required"`
+	Content      string               `json:"content" bson:"content" binding:"required"`
+	ContentHTML  string               `json:"contentHTML" bson:"content_html"`
+	Status       string               `json:"status" bson:"status"`
+	Tags         []string             `json:"tags" bson:"tags" binding:"max=5,dive,min=1,max=30"`
+	AnswerCount  int64                `json:"answerCount" bson:"answer_count"`
+	UserID       primitive.ObjectID   `json:"userID" bson:"user_id"`
+	UserName     string               `json:"userName" bson:"user_name"`
+	Upvote       int64                `json:"upvote" bson:"upvote"`
+	UpvoteBy     []primitive.ObjectID `json:"upvoteBy" bson:"upvote_by"`
+	CreatedAt    time.Time            `json:"createdAt" bson:"created_at"`
+	UpdatedAt    time.Time            `json:"updatedAt" bson:"updated_at"`
+	Mentions     []Mention            `json:"mentions,omitempty" bson:"mentions,omitempty"`
+	Unresolved   []string             `json:"unresolvedMentions,omitempty" bson:"unresolved_mentions,omitempty"`
+	Hidden       bool                 `json:"-" bson:"hidden,omitempty"`
+	Answers      []Answer             `json:"answers,omitempty" bson:"-"`
+	Comments     []Comment            `json:"comments,omitempty" bson:"-"`
+	CommentCount int64                `json:"commentCount" bson:"-"`
+	Bookmarked   bool                 `json:"bookmarked" bson:"-"`
+}
+
+// DuplicateQuestion holds an existing question similar to a new one
+type DuplicateQuestion struct {
+	ID          primitive.ObjectID `json:"questionID"`
+	Title       string             `json:"title"`
+	Status      string             `json:"status"`
+	AnswerCount int64              `json:"answerCount"`
+	Similarity  float64            `json:"similarity"`
+}
+
+// QuestionFeedQuery holds the filters, sorting and pagination of the global question feed
+type QuestionFeedQuery struct {
+	Tags     string    `form:"tags"`
+	Status   string    `form:"status" binding:"omitempty,oneof=Unresolved Resolved"`
+	AuthorID string    `form:"author"`
+	SkillID  string    `form:"skillID"`
+	From     time.Time `form:"from" time_format:"2006-01-02"`
+	To       time.Time `form:"to" time_format:"2006-01-02"`
+	SortBy   string    `form:"sortBy" binding:"omitempty,oneof=newest votes unanswered"`
+	Page     int64     `form:"page,default=1" binding:"min=1"`
+	Limit    int64     `form:"limit,default=20" binding:"min=1,max=100"`
+}
+
+// TagCount holds a question tag with the number of questions using it
+type TagCount struct {
+	Tag   string `json:"tag" bson:"_id"`
+	Count int64  `json:"count" bson:"count"`
 }
 
 // Add inserts a question document
@@ -42,11 +83,22 @@ func (qu *Question) Add() error {
 	return nil
 }
 
+// Get gets the question document based on the given filter
+func (qu *Question) Get(filters []bson.E) error {
+	err := config.QuestionCollection.FindOne(context.TODO(), bson.D(filters)).Decode(qu)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error getting question document -> %s", err.Error()))
+		return err
+	}
+
+	return nil
+}
+
 // GetAll gets the question documents
-func (qu *Question) GetAll(filters []bson.E) ([]Question, error) {
+func (qu *Question) GetAll(filters []bson.E, opts ...*options.FindOptions) ([]Question, error) {
 	questions := make([]Question, 0)
 
-	cursor, err := config.QuestionCollection.Find(context.TODO(), bson.D(filters))
+	cursor, err := config.QuestionCollection.Find(context.TODO(), bson.D(filters), opts...)
 	if err != nil {
 		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error fetching question documents -> %s", err.Error()))
 		return nil, err
@@ -61,6 +113,68 @@ func (qu *Question) GetAll(filters []bson.E) ([]Question, error) {
 	return questions, nil
 }
 
+// GetAllWithAnswers gets the question documents with their answers, accepted first then by score, in a single aggregation
+func (qu *Question) GetAllWithAnswers(filters []bson.E, sort bson.D) ([]Question, error) {
+	questions := make([]Question, 0)
+
+	pipeline := mongo.Pipeline{
+		{{"$match", bson.D(filters)}},
+	}
+
+	if len(sort) > 0 {
+		pipeline = append(pipeline, bson.D{{"$sort", sort}})
+	}
+
+	pipeline = append(pipeline, bson.D{{"$lookup", bson.D{
+		{"from", config.AnswerCollection.Name()},
+		{"localField", "_id"},
+		{"foreignField", "question_id"},
+		{"pipeline", mongo.Pipeline{
+			{{"$match", bson.D{{"hidden", bson.D{{"$ne", true}}}}}},
+			{{"$sort", bson.D{{"accepted", -1}, {"score", -1}, {"created_at", 1}}}},
+			{{"$project", bson.D{
+				{"question_id", 1},
+				{"content", 1},
+				{"content_html", 1},
+				{"user_id", 1},
+				{"user_name", 1},
+				{"upvote_by", 1},
+				{"downvote_by", 1},
+				{"score", 1},
+				{"accepted", 1},
+				{"created_at", 1},
+				{"updated_at", 1},
+			}}},
+		}},
+		{"as", "answers"},
+	}}})
+
+	cursor, err := config.QuestionCollection.Aggregate(context.TODO(), pipeline)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error aggregating questions with answers -> %s", err.Error()))
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	for cursor.Next(context.TODO()) {
+		var details struct {
+			Question `bson:",inline"`
+			Answers  []Answer `bson:"answers"`
+		}
+
+		err = cursor.Decode(&details)
+		if err != nil {
+			logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error decoding question with answers -> %s", err.Error()))
+			return nil, err
+		}
+
+		details.Question.Answers = details.Answers
+		questions = append(questions, details.Question)
+	}
+
+	return questions, cursor.Err()
+}
+
 // Update updates fields of a specific question
 func (qu *Question) Update(questionID primitive.ObjectID, update bson.D) error {
 	_, err := config.QuestionCollection.UpdateByID(context.TODO(), questionID, update)
@@ -71,3 +185,322 @@ func (qu *Question) Update(questionID primitive.ObjectID, update bson.D) error {
 
 	return nil
 }
+
+// AddUpvote atomically adds the user to the upvoters of a question, returning false if the user had already upvoted
+func (qu *Question) AddUpvote(questionID, userID primitive.ObjectID) (bool, error) {
+	filter := bson.D{
+		{"_id", questionID},
+		{"upvote_by", bson.D{{"$ne", userID}}},
+	}
+
+	update := mongo.Pipeline{
+		{{"$set", bson.D{
+			{"upvote_by", bson.D{{"$concatArrays", bson.A{bson.D{{"$ifNull", bson.A{"$upvote_by", bson.A{}}}}, bson.A{userID}}}}},
+			{"upvote", bson.D{{"$add", bson.A{bson.D{{"$ifNull", bson.A{"$upvote", 0}}}, 1}}}},
+		}}},
+	}
+
+	res, err := config.QuestionCollection.UpdateOne(context.TODO(), filter, update)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error upvoting question [%s] -> %s", questionID.Hex(), err.Error()))
+		return false, err
+	}
+
+	return res.ModifiedCount > 0, nil
+}
+
+// RemoveUpvote atomically removes the user from the upvoters of a question, returning false if the user had not upvoted
+func (qu *Question) RemoveUpvote(questionID, userID primitive.ObjectID) (bool, error) {
+	filter := bson.D{
+		{"_id", questionID},
+		{"upvote_by", userID},
+	}
+
+	update := bson.D{
+		{"$pull", bson.D{{"upvote_by", userID}}},
+		{"$inc", bson.D{{"upvote", -1}}},
+	}
+
+	res, err := config.QuestionCollection.UpdateOne(context.TODO(), filter, update)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error removing upvote of question [%s] -> %s", questionID.Hex(), err.Error()))
+		return false, err
+	}
+
+	return res.ModifiedCount > 0, nil
+}
+
+// Delete removes a specific question along with its answers
+func (qu *Question) Delete(questionID primitive.ObjectID) error {
+	res, err := config.QuestionCollection.DeleteOne(context.TODO(), bson.D{{"_id", questionID}})
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error deleting question [%s] -> %s", questionID.Hex(), err.Error()))
+		return err
+	}
+
+	if res.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	_, err = config.AnswerCollection.DeleteMany(context.TODO(), bson.D{{"question_id", questionID}})
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error deleting answers of question [%s] -> %s", questionID.Hex(), err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+// FindDuplicates gets the recent visible questions of the same skill whose title and content are similar to the question
+func (qu *Question) FindDuplicates() ([]DuplicateQuestion, error) {
+	filters := []bson.E{
+		{"skill_id", qu.SkillID},
+		{"hidden", bson.D{{"$ne", true}}},
+	}
+
+	findOptions := options.Find().
+		SetSort(bson.D{{"created_at", -1}}).
+		SetLimit(config.DuplicateCorpusSize).
+		SetProjection(bson.D{{"title", 1}, {"content", 1}, {"status", 1}, {"answer_count", 1}})
+
+	candidates, err := qu.GetAll(filters, findOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	corpus := make([]similarity.Document, len(candidates))
+	byID := make(map[string]Question, len(candidates))
+
+	for idx, candidate := range candidates {
+		corpus[idx] = similarity.Document{ID: candidate.ID.Hex(), Title: candidate.Title, Content: candidate.Content}
+		byID[candidate.ID.Hex()] = candidate
+	}
+
+	query := similarity.Document{Title: qu.Title, Content: qu.Content}
+
+	duplicates := make([]DuplicateQuestion, 0)
+	for _, match := range similarity.Rank(query, corpus, config.DuplicateThreshold, config.DuplicateLimit) {
+		candidate := byID[match.ID]
+
+		duplicates = append(duplicates, DuplicateQuestion{
+			ID:          candidate.ID,
+			Title:       candidate.Title,
+			Status:      candidate.Status,
+			AnswerCount: candidate.AnswerCount,
+			Similarity:  match.Score,
+		})
+	}
+
+	return duplicates, nil
+}
+
+// MergeDuplicates moves the answers, comments, upvotes, tags, subscriptions and bookmarks of the duplicate questions
+// into the question and deletes the duplicates, keeping at most one accepted answer. The merge runs in a transaction
+// so a failure leaves the duplicates untouched
+func (qu *Question) MergeDuplicates(duplicates []Question) error {
+	duplicateIDs := make([]primitive.ObjectID, len(duplicates))
+	upvoteBy := make([]primitive.ObjectID, 0)
+	tags := make([]string, 0)
+
+	for idx, duplicate := range duplicates {
+		duplicateIDs[idx] = duplicate.ID
+		upvoteBy = append(upvoteBy, duplicate.UpvoteBy...)
+		tags = append(tags, duplicate.Tags...)
+	}
+
+	err := withTransaction(func(ctx mongo.SessionContext) error {
+		// A question that is already resolved keeps its own accepted answer
+		if qu.Status == config.QuestionResolved {
+			_, err := config.AnswerCollection.UpdateMany(
+				ctx,
+				bson.D{{"question_id", bson.D{{"$in", duplicateIDs}}}, {"accepted", true}},
+				bson.D{{"$set", bson.D{{"accepted", false}}}},
+			)
+			if err != nil {
+				logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error clearing accepted answers of duplicates -> %s", err.Error()))
+				return err
+			}
+		}
+
+		_, err := config.AnswerCollection.UpdateMany(
+			ctx,
+			bson.D{{"question_id", bson.D{{"$in", duplicateIDs}}}},
+			bson.D{{"$set", bson.D{{"question_id", qu.ID}}}},
+		)
+		if err != nil {
+			logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error moving answers of duplicates into question [%s] -> %s", qu.ID.Hex(), err.Error()))
+			return err
+		}
+
+		_, err = config.CommentCollection.UpdateMany(
+			ctx,
+			bson.D{{"post_id", bson.D{{"$in", duplicateIDs}}}},
+			bson.D{{"$set", bson.D{{"post_id", qu.ID}}}},
+		)
+		if err != nil {
+			logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error moving comments of duplicates into question [%s] -> %s", qu.ID.Hex(), err.Error()))
+			return err
+		}
+
+		err = moveUserTargets(ctx, config.SubscriptionCollection, duplicateIDs, qu.ID)
+		if err != nil {
+			logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error moving subscriptions of duplicates into question [%s] -> %s", qu.ID.Hex(), err.Error()))
+			return err
+		}
+
+		err = moveUserTargets(ctx, config.BookmarkCollection, duplicateIDs, qu.ID)
+		if err != nil {
+			logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error moving bookmarks of duplicates into question [%s] -> %s", qu.ID.Hex(), err.Error()))
+			return err
+		}
+
+		// Several duplicates may each have had an accepted answer, only the earliest stays accepted
+		accepted := make([]Answer, 0)
+
+		cursor, err := config.AnswerCollection.Find(
+			ctx,
+			bson.D{{"question_id", qu.ID}, {"accepted", true}},
+			options.Find().SetSort(bson.D{{"created_at", 1}}).SetProjection(bson.D{{"_id", 1}}),
+		)
+		if err != nil {
+			logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error fetching accepted answers of question [%s] -> %s", qu.ID.Hex(), err.Error()))
+			return err
+		}
+
+		err = cursor.All(ctx, &accepted)
+		if err != nil {
+			logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error decoding answer documents from cursor -> %s", err.Error()))
+			return err
+		}
+
+		status := config.QuestionUnresolved
+		if len(accepted) > 0 {
+			status = config.QuestionResolved
+
+			for _, ans := range accepted[1:] {
+				_, err = config.AnswerCollection.UpdateByID(ctx, ans.ID, bson.D{{"$set", bson.D{{"accepted", false}}}})
+				if err != nil {
+					logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error clearing accepted answer [%s] -> %s", ans.ID.Hex(), err.Error()))
+					return err
+				}
+			}
+		}
+
+		answerCount, err := config.AnswerCollection.CountDocuments(ctx, bson.D{{"question_id", qu.ID}})
+		if err != nil {
+			logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error counting answers of question [%s] -> %s", qu.ID.Hex(), err.Error()))
+			return err
+		}
+
+		mergedUpvoteBy := bson.D{{"$setUnion", bson.A{bson.D{{"$ifNull", bson.A{"$upvote_by", bson.A{}}}}, upvoteBy}}}
+		mergedTags := bson.D{{"$setUnion", bson.A{bson.D{{"$ifNull", bson.A{"$tags", bson.A{}}}}, tags}}}
+
+		update := mongo.Pipeline{
+			{{"$set", bson.D{
+				{"upvote_by", mergedUpvoteBy},
+				{"tags", bson.D{{"$slice", bson.A{mergedTags, config.MaxQuestionTags}}}},
+				{"answer_count", answerCount},
+				{"status", status},
+				{"updated_at", time.Now()},
+			}}},
+			{{"$set", bson.D{{"upvote", bson.D{{"$size", "$upvote_by"}}}}}},
+		}
+
+		_, err = config.QuestionCollection.UpdateByID(ctx, qu.ID, update)
+		if err != nil {
+			logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error merging duplicates into question [%s] -> %s", qu.ID.Hex(), err.Error()))
+			return err
+		}
+
+		_, err = config.QuestionCollection.DeleteMany(ctx, bson.D{{"_id", bson.D{{"$in", duplicateIDs}}}})
+		if err != nil {
+			logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error deleting merged duplicates -> %s", err.Error()))
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	logging.Logger.Info(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Merged %d duplicates into question [%s]", len(duplicates), qu.ID.Hex()))
+
+	return nil
+}
+
+// moveUserTargets moves the per user documents, unique by user and target, from the given targets onto the new target.
+// Users who already have a document for the new target keep theirs
+func moveUserTargets(ctx mongo.SessionContext, collection *mongo.Collection, targetIDs []primitive.ObjectID, newTargetID primitive.ObjectID) error {
+	docs := make([]bson.M, 0)
+
+	cursor, err := collection.Find(ctx, bson.D{{"target_id", bson.D{{"$in", targetIDs}}}})
+	if err != nil {
+		return err
+	}
+
+	err = cursor.All(ctx, &docs)
+	if err != nil {
+		return err
+	}
+
+	for _, doc := range docs {
+		delete(doc, "_id")
+		doc["target_id"] = newTargetID
+
+		_, err = collection.UpdateOne(
+			ctx,
+			bson.D{{"user_id", doc["user_id"]}, {"target_id", newTargetID}},
+			bson.D{{"$setOnInsert", doc}},
+			options.Update().SetUpsert(true),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = collection.DeleteMany(ctx, bson.D{{"target_id", bson.D{{"$in", targetIDs}}}})
+	return err
+}
+
+// Count counts the question documents matching the given filter
+func (qu *Question) Count(filters []bson.E) (int64, error) {
+	count, err := config.QuestionCollection.CountDocuments(context.TODO(), bson.D(filters))
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error counting question documents -> %s", err.Error()))
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// GetTags gets the most used question tags starting with the given prefix
+func (qu *Question) GetTags(prefix string, limit int64) ([]TagCount, error) {
+	tags := make([]TagCount, 0)
+
+	prefixFilter := bson.D{{"$regex", "^" + regexp.QuoteMeta(prefix)}}
+
+	pipeline := mongo.Pipeline{
+		{{"$match", bson.D{{"tags", prefixFilter}, {"hidden", bson.D{{"$ne", true}}}}}},
+		{{"$unwind", "$tags"}},
+		{{"$match", bson.D{{"tags", prefixFilter}}}},
+		{{"$group", bson.D{{"_id", "$tags"}, {"count", bson.D{{"$sum", 1}}}}}},
+		{{"$sort", bson.D{{"count", -1}, {"_id", 1}}}},
+		{{"$limit", limit}},
+	}
+
+	cursor, err := config.QuestionCollection.Aggregate(context.TODO(), pipeline)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error aggregating question tags -> %s", err.Error()))
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	err = cursor.All(context.TODO(), &tags)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error decoding question tags from cursor -> %s", err.Error()))
+		return nil, err
+	}
+
+	return tags, nil
+}
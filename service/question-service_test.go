@@ -0,0 +1,158 @@
+package service_test
+
+import (
+	"career-compass-go/config"
+	"career-compass-go/pkg/logging"
+	"career-compass-go/pkg/setting"
+	"career-compass-go/service"
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"os"
+	"testing"
+	"time"
+)
+
+// testDB is the scratch database the tests and benchmarks seed, nil when mongo is unreachable
+var testDB *mongo.Database
+
+func TestMain(m *testing.M) {
+	logging.Setup()
+
+	client, err := setting.ConnectToMongo(config.ViperConfig.GetString("MONGO_URI"))
+	if err == nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		err = client.Ping(ctx, nil)
+		cancel()
+	}
+
+	if err == nil {
+		testDB = client.Database(fmt.Sprintf("%s_test_%d", config.MongoDBName, time.Now().UnixNano()))
+		config.UserCollection = testDB.Collection("users")
+		config.QuestionCollection = testDB.Collection("questions")
+		config.AnswerCollection = testDB.Collection("answers")
+		config.ReputationCollection = testDB.Collection("reputations")
+		config.RoleCollection = testDB.Collection("roles")
+		config.SkillCollection = testDB.Collection("skills")
+		config.CompanyCollection = testDB.Collection("companies")
+		config.ResourceCollection = testDB.Collection("resources")
+	}
+
+	code := m.Run()
+
+	// Drop the scratch database whatever the outcome of the run
+	if testDB != nil {
+		_ = testDB.Drop(context.TODO())
+		setting.CloseMongoClient(client)
+	}
+
+	os.Exit(code)
+}
+
+// seedQuestions inserts the questions with their answers for a new skill and returns the skill ID
+func seedQuestions(b *testing.B, questions, answers int) primitive.ObjectID {
+	b.Helper()
+
+	if testDB == nil {
+		b.Skip("mongo is unreachable at MONGO_URI")
+	}
+
+	_, err := config.AnswerCollection.Indexes().CreateOne(context.TODO(), mongo.IndexModel{Keys: bson.D{{Key: "question_id", Value: 1}}})
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	skillID := primitive.NewObjectID()
+	currTime := time.Now()
+
+	questionDocs := make([]any, questions)
+	answerDocs := make([]any, 0, questions*answers)
+
+	for i := range questionDocs {
+		questionID := primitive.NewObjectID()
+
+		questionDocs[i] = service.Question{
+			ID:        questionID,
+			SkillID:   skillID,
+			Title:     fmt.Sprintf("Question %d", i),
+			Content:   "How do I get started with this skill?",
+			Status:    config.QuestionUnresolved,
+			CreatedAt: currTime,
+			UpdatedAt: currTime,
+		}
+
+		for j := 0; j < answers; j++ {
+			answerDocs = append(answerDocs, service.Answer{
+				QuestionID: questionID,
+				Content:    fmt.Sprintf("Answer %d", j),
+				Score:      int64(j),
+				CreatedAt:  currTime,
+				UpdatedAt:  currTime,
+			})
+		}
+	}
+
+	_, err = config.QuestionCollection.InsertMany(context.TODO(), questionDocs)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	if len(answerDocs) > 0 {
+		_, err = config.AnswerCollection.InsertMany(context.TODO(), answerDocs)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	return skillID
+}
+
+// benchmarkSizes runs the fetch against skills seeded with a growing number of questions, seeding each skill once
+// rather than on every round of the benchmark
+func benchmarkSizes(b *testing.B, fetch func(filters []bson.E) error) {
+	for _, questions := range []int{100, 1000} {
+		filters := []bson.E{{"skill_id", seedQuestions(b, questions, 3)}}
+
+		b.Run(fmt.Sprintf("questions=%d", questions), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				err := fetch(filters)
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkQuestionsPerQuestionLookups(b *testing.B) {
+	benchmarkSizes(b, func(filters []bson.E) error {
+		var question service.Question
+
+		resp, err := question.GetAll(filters)
+		if err != nil {
+			return err
+		}
+
+		for idx, ques := range resp {
+			var ans service.Answer
+
+			resp[idx].Answers, err = ans.GetAll([]bson.E{{"question_id", ques.ID}})
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func BenchmarkQuestionsLookupAggregation(b *testing.B) {
+	benchmarkSizes(b, func(filters []bson.E) error {
+		var question service.Question
+
+		_, err := question.GetAllWithAnswers(filters, nil)
+		return err
+	})
+}
@@ -0,0 +1,303 @@
+package service
+
+import (
+	"career-compass-go/config"
+	"career-compass-go/pkg/logging"
+	"career-compass-go/utils"
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// Reputation collection schema holding the reputation a user earned within a skill
+type Reputation struct {
+	ID            primitive.ObjectID `json:"-" bson:"_id,omitempty"`
+	UserID        primitive.ObjectID `json:"userID" bson:"user_id"`
+	UserName      string             `json:"userName,omitempty" bson:"user_name,omitempty"`
+	SkillID       primitive.ObjectID `json:"skillID" bson:"skill_id"`
+	Reputation    int64              `json:"reputation" bson:"reputation"`
+	AnswerCount   int64              `json:"answerCount" bson:"answer_count"`
+	AcceptedCount int64              `json:"acceptedCount" bson:"accepted_count"`
+}
+
+// recalculateMu serialises the reputation recalculations of this process. Recalculations in other processes, such as
+// cmd/reputation, may still interleave with them; the upserts under the unique user and skill index keep a single
+// document per user and skill whatever the interleaving, and the last run to write a user wins
+var recalculateMu sync.Mutex
+
+// Badge holds an achievement awarded to a user for their Q&A activity
+type Badge struct {
+	Name      string             `json:"name" bson:"name"`
+	SkillID   primitive.ObjectID `json:"skillID,omitempty" bson:"skill_id,omitempty"`
+	AwardedAt time.Time          `json:"awardedAt" bson:"awarded_at"`
+}
+
+// GetAll gets the reputation documents sorted from the highest
+func (rp *Reputation) GetAll(filters []bson.E) ([]Reputation, error) {
+	reputations := make([]Reputation, 0)
+
+	cursor, err := config.ReputationCollection.Find(context.TODO(), bson.D(filters), options.Find().SetSort(bson.D{{"reputation", -1}}))
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error fetching reputation documents -> %s", err.Error()))
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	err = cursor.All(context.TODO(), &reputations)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error decoding reputation documents from cursor -> %s", err.Error()))
+		return nil, err
+	}
+
+	return reputations, nil
+}
+
+// GetLeaderboard gets the users with the highest reputation within a skill along with their usernames
+func (rp *Reputation) GetLeaderboard(skillID primitive.ObjectID, limit int64) ([]Reputation, error) {
+	leaderboard := make([]Reputation, 0)
+
+	pipeline := mongo.Pipeline{
+		{{"$match", bson.D{{"skill_id", skillID}, {"reputation", bson.D{{"$gt", 0}}}}}},
+		{{"$sort", bson.D{{"reputation", -1}, {"accepted_count", -1}}}},
+		{{"$limit", limit}},
+		{{"$lookup", bson.D{
+			{"from", config.UserCollection.Name()},
+			{"localField", "user_id"},
+			{"foreignField", "_id"},
+			{"pipeline", mongo.Pipeline{{{"$project", bson.D{{"username", 1}}}}}},
+			{"as", "user"},
+		}}},
+		{{"$set", bson.D{{"user_name", bson.D{{"$first", "$user.username"}}}}}},
+		{{"$unset", "user"}},
+	}
+
+	cursor, err := config.ReputationCollection.Aggregate(context.TODO(), pipeline)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error aggregating skill leaderboard -> %s", err.Error()))
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	err = cursor.All(context.TODO(), &leaderboard)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error decoding skill leaderboard from cursor -> %s", err.Error()))
+		return nil, err
+	}
+
+	return leaderboard, nil
+}
+
+// Recalculate recomputes the reputation and badges of the given users from their questions and answers,
+// or of every user when no user is given. Recalculations in this process run one at a time so an older one cannot
+// overwrite the result of a newer one
+func (rp *Reputation) Recalculate(userIDs []primitive.ObjectID) error {
+	recalculateMu.Lock()
+	defer recalculateMu.Unlock()
+
+	userFilter := bson.D{}
+	if len(userIDs) > 0 {
+		userFilter = bson.D{{"user_id", bson.D{{"$in", userIDs}}}}
+	}
+
+	// votersOf counts the voters in an array field that may be missing, leaving out the post author
+	votersOf := func(field string) bson.D {
+		return bson.D{{"$size", bson.D{{"$setDifference", bson.A{bson.D{{"$ifNull", bson.A{field, bson.A{}}}}, bson.A{"$user_id"}}}}}}
+	}
+
+	// Authors accepting their own answer earn nothing from it
+	acceptedByOther := bson.D{{"$and", bson.A{"$accepted", bson.D{{"$ne", bson.A{"$user_id", "$question.user_id"}}}}}}
+
+	// Legacy question upvotes carry no voters, so the author's own upvote is taken off the count
+	upvotesByOthers := bson.D{{"$subtract", bson.A{
+		bson.D{{"$ifNull", bson.A{"$upvote", 0}}},
+		bson.D{{"$cond", bson.A{bson.D{{"$in", bson.A{"$user_id", bson.D{{"$ifNull", bson.A{"$upvote_by", bson.A{}}}}}}}, 1, 0}}},
+	}}}
+
+	pipeline := mongo.Pipeline{
+		{{"$match", userFilter}},
+		{{"$lookup", bson.D{
+			{"from", config.QuestionCollection.Name()},
+			{"localField", "question_id"},
+			{"foreignField", "_id"},
+			{"pipeline", mongo.Pipeline{{{"$project", bson.D{{"skill_id", 1}, {"user_id", 1}}}}}},
+			{"as", "question"},
+		}}},
+		{{"$unwind", "$question"}},
+		{{"$project", bson.D{
+			{"user_id", 1},
+			{"skill_id", "$question.skill_id"},
+			{"reputation", bson.D{{"$add", bson.A{
+				bson.D{{"$multiply", bson.A{votersOf("$upvote_by"), config.AnswerUpvoteReputation}}},
+				bson.D{{"$multiply", bson.A{votersOf("$downvote_by"), config.AnswerDownvoteReputation}}},
+				bson.D{{"$cond", bson.A{acceptedByOther, config.AcceptedAnswerReputation, 0}}},
+			}}}},
+			{"answer_count", bson.D{{"$literal", 1}}},
+			{"accepted_count", bson.D{{"$cond", bson.A{acceptedByOther, 1, 0}}}},
+		}}},
+		{{"$unionWith", bson.D{
+			{"coll", config.QuestionCollection.Name()},
+			{"pipeline", mongo.Pipeline{
+				{{"$match", userFilter}},
+				{{"$project", bson.D{
+					{"user_id", 1},
+					{"skill_id", 1},
+					{"reputation", bson.D{{"$multiply", bson.A{upvotesByOthers, config.QuestionUpvoteReputation}}}},
+					{"answer_count", bson.D{{"$literal", 0}}},
+					{"accepted_count", bson.D{{"$literal", 0}}},
+				}}},
+			}},
+		}}},
+		{{"$group", bson.D{
+			{"_id", bson.D{{"user_id", "$user_id"}, {"skill_id", "$skill_id"}}},
+			{"reputation", bson.D{{"$sum", "$reputation"}}},
+			{"answer_count", bson.D{{"$sum", "$answer_count"}}},
+			{"accepted_count", bson.D{{"$sum", "$accepted_count"}}},
+		}}},
+		{{"$project", bson.D{
+			{"_id", 0},
+			{"user_id", "$_id.user_id"},
+			{"skill_id", "$_id.skill_id"},
+			{"reputation", 1},
+			{"answer_count", 1},
+			{"accepted_count", 1},
+		}}},
+	}
+
+	cursor, err := config.AnswerCollection.Aggregate(context.TODO(), pipeline)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error aggregating reputations -> %s", err.Error()))
+		return err
+	}
+	defer cursor.Close(context.TODO())
+
+	reputations := make([]Reputation, 0)
+
+	err = cursor.All(context.TODO(), &reputations)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error decoding reputations from cursor -> %s", err.Error()))
+		return err
+	}
+
+	// Upsert the per skill reputations, unique by user and skill, stamping them with the time of this run
+	recalculatedAt := time.Now()
+	byUser := make(map[primitive.ObjectID][]Reputation)
+	models := make([]mongo.WriteModel, len(reputations))
+
+	for idx, reputation := range reputations {
+		byUser[reputation.UserID] = append(byUser[reputation.UserID], reputation)
+
+		models[idx] = mongo.NewUpdateOneModel().
+			SetFilter(bson.D{{"user_id", reputation.UserID}, {"skill_id", reputation.SkillID}}).
+			SetUpdate(bson.D{{"$set", bson.D{
+				{"reputation", reputation.Reputation},
+				{"answer_count", reputation.AnswerCount},
+				{"accepted_count", reputation.AcceptedCount},
+				{"recalculated_at", recalculatedAt},
+			}}}).
+			SetUpsert(true)
+	}
+
+	if len(models) > 0 {
+		_, err = config.ReputationCollection.BulkWrite(context.TODO(), models, options.BulkWrite().SetOrdered(false))
+		if err != nil {
+			logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error upserting reputations -> %s", err.Error()))
+			return err
+		}
+	}
+
+	// Skills in which the recalculated users no longer have any activity were not stamped by this run
+	staleFilter := append(userFilter, bson.E{"recalculated_at", bson.D{{"$not", bson.D{{"$gte", recalculatedAt}}}}})
+
+	_, err = config.ReputationCollection.DeleteMany(context.TODO(), staleFilter)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error clearing stale reputations -> %s", err.Error()))
+		return err
+	}
+
+	// Users without any activity left lose their reputation and badges as well
+	if len(userIDs) == 0 {
+		for userID := range byUser {
+			userIDs = append(userIDs, userID)
+		}
+
+		_, err = config.UserCollection.UpdateMany(
+			context.TODO(),
+			bson.D{{"_id", bson.D{{"$nin", userIDs}}}},
+			bson.D{{"$set", bson.D{{"reputation", 0}, {"badges", bson.A{}}}}},
+		)
+		if err != nil {
+			logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error resetting reputation of inactive users -> %s", err.Error()))
+			return err
+		}
+	}
+
+	for _, userID := range userIDs {
+		err = updateUserReputation(userID, byUser[userID])
+		if err != nil {
+			return err
+		}
+	}
+
+	logging.Logger.Info(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Recalculated reputation of %d users", len(userIDs)))
+
+	return nil
+}
+
+// updateUserReputation stores the total reputation of a user and awards the badges earned from the per skill reputations
+func updateUserReputation(userID primitive.ObjectID, reputations []Reputation) error {
+	var user User
+
+	err := user.Get([]bson.E{{"_id", userID}})
+	if err != nil {
+		return err
+	}
+
+	// Badges keep the time they were first awarded
+	awardedAt := make(map[string]time.Time)
+	for _, badge := range user.Badges {
+		awardedAt[badge.Name+badge.SkillID.Hex()] = badge.AwardedAt
+	}
+
+	badges := make([]Badge, 0)
+	award := func(name string, skillID primitive.ObjectID) {
+		badge := Badge{Name: name, SkillID: skillID, AwardedAt: time.Now()}
+		if earlier, ok := awardedAt[name+skillID.Hex()]; ok {
+			badge.AwardedAt = earlier
+		}
+
+		badges = append(badges, badge)
+	}
+
+	var total, answers, accepted int64
+	for _, reputation := range reputations {
+		total += reputation.Reputation
+		answers += reputation.AnswerCount
+		accepted += reputation.AcceptedCount
+
+		if reputation.Reputation >= config.SkillExpertReputation {
+			award(config.SkillExpertBadge, reputation.SkillID)
+		}
+	}
+
+	if answers >= 1 {
+		award(config.FirstAnswerBadge, primitive.NilObjectID)
+	}
+	if accepted >= config.AcceptedAnswersBadgeCount {
+		award(config.AcceptedAnswersBadge, primitive.NilObjectID)
+	}
+
+	update := bson.D{
+		{"$set", bson.D{
+			{"reputation", total},
+			{"badges", badges},
+		}},
+	}
+
+	return user.Update([]bson.E{{"_id", userID}}, update)
+}
@@ -0,0 +1,87 @@
+package service_test
+
+import (
+	"career-compass-go/config"
+	"career-compass-go/service"
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"testing"
+	"time"
+)
+
+func TestRecalculateIgnoresOwnVotesAndAcceptance(t *testing.T) {
+	if testDB == nil {
+		t.Skip("mongo is unreachable at MONGO_URI")
+	}
+
+	authorID := primitive.NewObjectID()
+	voterID := primitive.NewObjectID()
+	skillID := primitive.NewObjectID()
+	currTime := time.Now()
+
+	_, err := config.UserCollection.InsertOne(context.TODO(), bson.D{{"_id", authorID}, {"username", "author"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	question := service.Question{
+		ID:        primitive.NewObjectID(),
+		UserID:    authorID,
+		SkillID:   skillID,
+		Title:     "How do I get started?",
+		Upvote:    2,
+		UpvoteBy:  []primitive.ObjectID{authorID, voterID},
+		Status:    config.QuestionResolved,
+		CreatedAt: currTime,
+		UpdatedAt: currTime,
+	}
+
+	// The author answers, upvotes and accepts their own question and answer
+	answer := service.Answer{
+		QuestionID: question.ID,
+		UserID:     authorID,
+		Content:    "Start with the basics.",
+		UpvoteBy:   []primitive.ObjectID{authorID, voterID},
+		Score:      2,
+		Accepted:   true,
+		CreatedAt:  currTime,
+		UpdatedAt:  currTime,
+	}
+
+	_, err = config.QuestionCollection.InsertOne(context.TODO(), question)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = config.AnswerCollection.InsertOne(context.TODO(), answer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var reputation service.Reputation
+
+	err = reputation.Recalculate([]primitive.ObjectID{authorID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reputations, err := reputation.GetAll([]bson.E{{"user_id", authorID}, {"skill_id", skillID}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(reputations) != 1 {
+		t.Fatalf("got %d reputation documents, want 1", len(reputations))
+	}
+
+	// Only the other user's upvotes count
+	want := int64(config.QuestionUpvoteReputation + config.AnswerUpvoteReputation)
+	if got := reputations[0].Reputation; got != want {
+		t.Errorf("reputation = %d, want %d", got, want)
+	}
+
+	if got := reputations[0].AcceptedCount; got != 0 {
+		t.Errorf("accepted count = %d, want 0", got)
+	}
+}
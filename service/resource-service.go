@@ -0,0 +1,301 @@
+package service
+
+import (
+	"career-compass-go/config"
+	"career-compass-go/pkg/logging"
+	"career-compass-go/utils"
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"runtime"
+	"time"
+)
+
+// Resource collection schema
+type Resource struct {
+	ID              primitive.ObjectID `json:"resourceID" bson:"_id,omitempty"`
+	SkillID         primitive.ObjectID `json:"skillID" bson:"skill_id"`
+	Title           string             `json:"title" bson:"title" binding:"required"`
+	URL             string             `json:"url" bson:"url" binding:"required,url"`
+	Type            string             `json:"type" bson:"type" binding:"required,oneof=video website course article book documentation"`
+	Provider        string             `json:"provider,omitempty" bson:"provider"`
+	DurationMinutes int                `json:"durationMinutes,omitempty" bson:"duration_minutes" binding:"gte=0"`
+	Cost            string             `json:"cost,omitempty" bson:"cost" binding:"omitempty,oneof=free paid subscription"`
+	Difficulty      string             `json:"difficulty,omitempty" bson:"difficulty" binding:"omitempty,oneof=beginner intermediate advanced"`
+	Language        string             `json:"language,omitempty" bson:"language"`
+	AddedBy         primitive.ObjectID `json:"addedBy" bson:"added_by"`
+	Ratings         []ResourceRating   `json:"-" bson:"ratings"`
+	RatingAverage   float64            `json:"ratingAverage" bson:"rating_average"`
+	RatingCount     int64              `json:"ratingCount" bson:"rating_count"`
+	Broken          bool               `json:"broken" bson:"broken"`
+	LinkFailures    int                `json:"-" bson:"link_failures"`
+	LinkCheckedAt   time.Time          `json:"linkCheckedAt,omitempty" bson:"link_checked_at,omitempty"`
+	LinkChecks      []LinkCheck        `json:"linkChecks,omitempty" bson:"link_checks,omitempty"`
+	CreatedAt       time.Time          `json:"createdAt" bson:"created_at"`
+	UpdatedAt       time.Time          `json:"updatedAt" bson:"updated_at"`
+}
+
+// ResourceRating holds the usefulness rating given by a user to a resource
+type ResourceRating struct {
+	UserID  primitive.ObjectID `json:"userID" bson:"user_id"`
+	Rating  int                `json:"rating" bson:"rating" binding:"required,min=1,max=5"`
+	RatedAt time.Time          `json:"ratedAt" bson:"rated_at"`
+}
+
+// LinkCheck holds the outcome of a single reachability check of a resource URL
+type LinkCheck struct {
+	CheckedAt  time.Time `json:"checkedAt" bson:"checked_at"`
+	StatusCode int       `json:"statusCode,omitempty" bson:"status_code,omitempty"`
+	Error      string    `json:"error,omitempty" bson:"error,omitempty"`
+	Failed     bool      `json:"failed" bson:"failed"`
+	LatencyMs  int64     `json:"latencyMs" bson:"latency_ms"`
+}
+
+// Add inserts a resource document
+func (re *Resource) Add() error {
+	res, err := config.ResourceCollection.InsertOne(context.TODO(), re)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error inserting new resource document -> %s", err.Error()))
+		return err
+	}
+
+	re.ID = res.InsertedID.(primitive.ObjectID)
+	logging.Logger.Info(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Created resourceID -> %s", re.ID.Hex()))
+
+	return nil
+}
+
+// Get gets the resource document based on the given filter
+func (re *Resource) Get(filters []bson.E) error {
+	err := config.ResourceCollection.FindOne(context.TODO(), bson.D(filters)).Decode(re)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error getting resource document -> %s", err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+// GetRanked gets the resource documents ranked by their usefulness ratings
+func (re *Resource) GetRanked(filters []bson.E) ([]Resource, error) {
+	resources := make([]Resource, 0)
+
+	findOptions := options.Find().SetSort(bson.D{{"rating_average", -1}, {"rating_count", -1}, {"created_at", 1}})
+
+	cursor, err := config.ResourceCollection.Find(context.TODO(), bson.D(filters), findOptions)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error getting resource documents -> %s", err.Error()))
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	err = cursor.All(context.TODO(), &resources)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error decoding resource documents from cursor -> %s", err.Error()))
+		return nil, err
+	}
+
+	return resources, nil
+}
+
+// Update updates fields of a specific resource
+func (re *Resource) Update(resourceID primitive.ObjectID, update bson.D) error {
+	res, err := config.ResourceCollection.UpdateByID(context.TODO(), resourceID, update)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error updating resource [%s] -> %s", resourceID.Hex(), err.Error()))
+		return err
+	}
+
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
+
+// Delete removes a specific resource
+func (re *Resource) Delete(resourceID primitive.ObjectID) error {
+	res, err := config.ResourceCollection.DeleteOne(context.TODO(), bson.D{{"_id", resourceID}})
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error deleting resource [%s] -> %s", resourceID.Hex(), err.Error()))
+		return err
+	}
+
+	if res.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
+
+// Rate atomically sets the user's rating on a resource and recomputes its average and count
+func (re *Resource) Rate(resourceID primitive.ObjectID, rating ResourceRating) error {
+	// Replace any earlier rating of the user so each user counts once
+	update := mongo.Pipeline{
+		{{"$set", bson.D{
+			{"ratings", bson.D{{"$concatArrays", bson.A{
+				bson.D{{"$filter", bson.D{
+					{"input", bson.D{{"$ifNull", bson.A{"$ratings", bson.A{}}}}},
+					{"as", "ra"},
+					{"cond", bson.D{{"$ne", bson.A{"$$ra.user_id", rating.UserID}}}},
+				}}},
+				bson.A{rating},
+			}}}},
+		}}},
+		{{"$set", bson.D{
+			{"rating_count", bson.D{{"$size", "$ratings"}}},
+			{"rating_average", bson.D{{"$avg", "$ratings.rating"}}},
+		}}},
+	}
+
+	res, err := config.ResourceCollection.UpdateByID(context.TODO(), resourceID, update)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error rating resource [%s] -> %s", resourceID.Hex(), err.Error()))
+		return err
+	}
+
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
+
+// GetLinks streams the ID and URL of every resource to the given function
+func (re *Resource) GetLinks(fn func(resourceID primitive.ObjectID, url string)) error {
+	cursor, err := config.ResourceCollection.Find(context.TODO(), bson.D{}, options.Find().SetProjection(bson.D{{"url", 1}}))
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error getting resource links -> %s", err.Error()))
+		return err
+	}
+	defer cursor.Close(context.TODO())
+
+	for cursor.Next(context.TODO()) {
+		var link struct {
+			ID  primitive.ObjectID `bson:"_id"`
+			URL string             `bson:"url"`
+		}
+
+		err = cursor.Decode(&link)
+		if err != nil {
+			logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error decoding resource link -> %s", err.Error()))
+			return err
+		}
+
+		fn(link.ID, link.URL)
+	}
+
+	return cursor.Err()
+}
+
+// RecordLinkCheck appends the check to the bounded status history of a resource and flags it broken after consecutive failures
+func (re *Resource) RecordLinkCheck(resourceID primitive.ObjectID, check LinkCheck) error {
+	failures := bson.D{{"$add", bson.A{bson.D{{"$ifNull", bson.A{"$link_failures", 0}}}, 1}}}
+	if !check.Failed {
+		failures = bson.D{{"$literal", 0}}
+	}
+
+	update := mongo.Pipeline{
+		{{"$set", bson.D{
+			{"link_checks", bson.D{{"$slice", bson.A{
+				bson.D{{"$concatArrays", bson.A{
+					bson.D{{"$ifNull", bson.A{"$link_checks", bson.A{}}}},
+					bson.D{{"$literal", bson.A{check}}},
+				}}},
+				-config.LinkCheckHistory,
+			}}}},
+			{"link_failures", failures},
+			{"link_checked_at", check.CheckedAt},
+		}}},
+		{{"$set", bson.D{
+			{"broken", bson.D{{"$gte", bson.A{"$link_failures", config.LinkBrokenThreshold}}}},
+		}}},
+	}
+
+	_, err := config.ResourceCollection.UpdateByID(context.TODO(), resourceID, update)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error recording link check of resource [%s] -> %s", resourceID.Hex(), err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+// MigrateLegacy converts the bare youtube, website and course links of the skill documents into resources
+func (re *Resource) MigrateLegacy() error {
+	filter := bson.D{{"$or", bson.A{
+		bson.D{{"youtube", bson.D{{"$exists", true}}}},
+		bson.D{{"website", bson.D{{"$exists", true}}}},
+		bson.D{{"courses", bson.D{{"$exists", true}}}},
+	}}}
+
+	projection := bson.D{{"youtube", 1}, {"website", 1}, {"courses", 1}}
+
+	cursor, err := config.SkillCollection.Find(context.TODO(), filter, options.Find().SetProjection(projection))
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error getting skill documents with legacy resources -> %s", err.Error()))
+		return err
+	}
+	defer cursor.Close(context.TODO())
+
+	for cursor.Next(context.TODO()) {
+		var legacy struct {
+			ID      primitive.ObjectID `bson:"_id"`
+			Youtube []string           `bson:"youtube"`
+			Website []string           `bson:"website"`
+			Courses []string           `bson:"courses"`
+		}
+
+		err = cursor.Decode(&legacy)
+		if err != nil {
+			logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error decoding legacy resources -> %s", err.Error()))
+			return err
+		}
+
+		currTime := time.Now()
+		resources := make([]any, 0, len(legacy.Youtube)+len(legacy.Website)+len(legacy.Courses))
+
+		for resourceType, links := range map[string][]string{
+			config.ResourceTypeVideo:   legacy.Youtube,
+			config.ResourceTypeWebsite: legacy.Website,
+			config.ResourceTypeCourse:  legacy.Courses,
+		} {
+			for _, link := range links {
+				resources = append(resources, Resource{
+					SkillID:   legacy.ID,
+					Title:     link,
+					URL:       link,
+					Type:      resourceType,
+					Ratings:   []ResourceRating{},
+					CreatedAt: currTime,
+					UpdatedAt: currTime,
+				})
+			}
+		}
+
+		if len(resources) > 0 {
+			_, err = config.ResourceCollection.InsertMany(context.TODO(), resources)
+			if err != nil {
+				logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error inserting resources of skill [%s] -> %s", legacy.ID.Hex(), err.Error()))
+				return err
+			}
+		}
+
+		update := bson.D{
+			{"$unset", bson.D{{"youtube", ""}, {"website", ""}, {"courses", ""}}},
+		}
+
+		_, err = config.SkillCollection.UpdateByID(context.TODO(), legacy.ID, update)
+		if err != nil {
+			logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error removing legacy resources of skill [%s] -> %s", legacy.ID.Hex(), err.Error()))
+			return err
+		}
+
+		logging.Logger.Info(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Migrated %d resources of skill [%s]", len(resources), legacy.ID.Hex()))
+	}
+
+	return cursor.Err()
+}
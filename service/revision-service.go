@@ -0,0 +1,71 @@
+package service
+
+import (
+	"career-compass-go/config"
+	"career-compass-go/pkg/logging"
+	"career-compass-go/utils"
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"runtime"
+	"time"
+)
+
+// Revision collection schema holding a replaced version of a question or answer
+type Revision struct {
+	ID           primitive.ObjectID `json:"revisionID" bson:"_id,omitempty"`
+	PostID       primitive.ObjectID `json:"postID" bson:"post_id"`
+	PostType     string             `json:"postType" bson:"post_type"`
+	Title        string             `json:"title,omitempty" bson:"title,omitempty"`
+	Content      string             `json:"content" bson:"content"`
+	EditedBy     primitive.ObjectID `json:"editedBy" bson:"edited_by"`
+	EditedByName string             `json:"editedByName" bson:"edited_by_name"`
+	CreatedAt    time.Time          `json:"createdAt" bson:"created_at"`
+}
+
+// Add inserts a revision document
+func (rv *Revision) Add() error {
+	res, err := config.RevisionCollection.InsertOne(context.TODO(), rv)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error inserting new revision document -> %s", err.Error()))
+		return err
+	}
+
+	rv.ID = res.InsertedID.(primitive.ObjectID)
+	logging.Logger.Info(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Created revisionID -> %s", rv.ID.Hex()))
+
+	return nil
+}
+
+// GetAll gets the revision documents from the newest to the oldest
+func (rv *Revision) GetAll(filters []bson.E) ([]Revision, error) {
+	revisions := make([]Revision, 0)
+
+	cursor, err := config.RevisionCollection.Find(context.TODO(), bson.D(filters), options.Find().SetSort(bson.D{{"created_at", -1}}))
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error fetching revision documents -> %s", err.Error()))
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	err = cursor.All(context.TODO(), &revisions)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error decoding revision documents from cursor -> %s", err.Error()))
+		return nil, err
+	}
+
+	return revisions, nil
+}
+
+// DeleteAll removes the revision documents of the given posts
+func (rv *Revision) DeleteAll(postIDs []primitive.ObjectID) error {
+	_, err := config.RevisionCollection.DeleteMany(context.TODO(), bson.D{{"post_id", bson.D{{"$in", postIDs}}}})
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error deleting revision documents -> %s", err.Error()))
+		return err
+	}
+
+	return nil
+}
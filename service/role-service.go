@@ -8,7 +8,10 @@ import (
 	"fmt"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 	"runtime"
+	"time"
 )
 
 // Role collection schema
@@ -18,16 +21,55 @@ type Role struct {
 	Name        string               `json:"name" bson:"name"`
 	Image       string               `json:"image" bson:"image"`
 	Description string               `json:"description,omitempty" bson:"description"`
-	Salary      string               `json:"salary,omitempty" bson:"salary"`
+	Salaries    []Salary             `json:"salaries,omitempty" bson:"salaries" binding:"omitempty,dive"`
+	SalaryText  string               `json:"salaryText,omitempty" bson:"salary,omitempty"`
 	Duties      []string             `json:"duties,omitempty" bson:"duties"`
-	Companies   []Company            `json:"companies,omitempty" bson:"companies"`
+	CompanyIDs  []primitive.ObjectID `json:"companyIDs,omitempty" bson:"company_ids"`
+	Companies   []Company            `json:"companies,omitempty" bson:"-"`
 	Skills      []Skill              `json:"skills,omitempty" bson:"-"`
+	Bookmarked  bool                 `json:"bookmarked" bson:"-"`
 }
 
-type Company struct {
-	Name  string `json:"name,omitempty" bson:"name"`
-	Image string `json:"image,omitempty" bson:"image"`
-	Link  string `json:"link,omitempty" bson:"link"`
+// Salary holds a structured salary range of a role for a region and experience level
+type Salary struct {
+	Min             float64   `json:"min" bson:"min" binding:"gte=0"`
+	Max             float64   `json:"max" bson:"max" binding:"gtefield=Min"`
+	Median          float64   `json:"median,omitempty" bson:"median,omitempty" binding:"omitempty,gte=0"`
+	Currency        string    `json:"currency" bson:"currency" binding:"required,len=3,uppercase"`
+	Period          string    `json:"period" bson:"period" binding:"required,oneof=yearly monthly hourly"`
+	Region          string    `json:"region,omitempty" bson:"region,omitempty"`
+	ExperienceLevel string    `json:"experienceLevel,omitempty" bson:"experience_level,omitempty"`
+	Source          string    `json:"source,omitempty" bson:"source,omitempty"`
+	AsOf            time.Time `json:"asOf,omitempty" bson:"as_of,omitempty"`
+}
+
+// SalaryFilter holds the salary query filters for role listing and comparison
+type SalaryFilter struct {
+	Currency        string  `form:"currency"`
+	Period          string  `form:"period"`
+	Region          string  `form:"region"`
+	ExperienceLevel string  `form:"experienceLevel"`
+	Min             float64 `form:"minSalary" binding:"gte=0"`
+	Max             float64 `form:"maxSalary" binding:"omitempty,gtefield=Min"`
+}
+
+// RoleComparison holds the shared and distinct details across a set of compared roles
+type RoleComparison struct {
+	Roles           []ComparedRole `json:"roles" bson:"roles"`
+	SharedSkills    []Skill        `json:"sharedSkills" bson:"shared_skills"`
+	SharedDuties    []string       `json:"sharedDuties" bson:"shared_duties"`
+	SharedCompanies []Company      `json:"sharedCompanies" bson:"shared_companies"`
+}
+
+// ComparedRole holds the details of a role that are distinct from the other compared roles
+type ComparedRole struct {
+	ID                primitive.ObjectID `json:"roleID" bson:"_id"`
+	Name              string             `json:"name" bson:"name"`
+	Image             string             `json:"image" bson:"image"`
+	Salaries          []Salary           `json:"salaries" bson:"salaries"`
+	DistinctSkills    []Skill            `json:"distinctSkills" bson:"distinct_skills"`
+	DistinctDuties    []string           `json:"distinctDuties" bson:"distinct_duties"`
+	DistinctCompanies []Company          `json:"distinctCompanies" bson:"distinct_companies"`
 }
 
 // Create inserts a new role document
@@ -55,11 +97,78 @@ func (r *Role) Get(filters []bson.E) error {
 	return nil
 }
 
+// GetByName gets the role document with the given name, ignoring case
+func (r *Role) GetByName(name string) error {
+	opts := options.FindOne().SetCollation(&options.Collation{Locale: "en", Strength: 2})
+
+	err := config.RoleCollection.FindOne(context.TODO(), bson.D{{"name", name}}, opts).Decode(r)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error getting role document named [%s] -> %s", name, err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+// GetDetails gets the role document with its linked skills and companies in a single aggregation
+func (r *Role) GetDetails(roleID primitive.ObjectID) error {
+	pipeline := mongo.Pipeline{
+		{{"$match", bson.D{{"_id", roleID}}}},
+		{{"$lookup", bson.D{
+			{"from", config.SkillCollection.Name()},
+			{"localField", "skill_ids"},
+			{"foreignField", "_id"},
+			{"pipeline", mongo.Pipeline{{{"$project", bson.D{{"name", 1}, {"image", 1}, {"rating_dimension", 1}}}}}},
+			{"as", "skills"},
+		}}},
+		{{"$lookup", bson.D{
+			{"from", config.CompanyCollection.Name()},
+			{"localField", "company_ids"},
+			{"foreignField", "_id"},
+			{"as", "companies"},
+		}}},
+	}
+
+	cursor, err := config.RoleCollection.Aggregate(context.TODO(), pipeline)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error aggregating role details -> %s", err.Error()))
+		return err
+	}
+	defer cursor.Close(context.TODO())
+
+	if !cursor.Next(context.TODO()) {
+		if cursor.Err() != nil {
+			logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error reading role details cursor -> %s", cursor.Err().Error()))
+			return cursor.Err()
+		}
+
+		return mongo.ErrNoDocuments
+	}
+
+	var details struct {
+		Role      `bson:",inline"`
+		Skills    []Skill   `bson:"skills"`
+		Companies []Company `bson:"companies"`
+	}
+
+	err = cursor.Decode(&details)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error decoding role details -> %s", err.Error()))
+		return err
+	}
+
+	*r = details.Role
+	r.Skills = orderByIDs(details.Skills, r.SkillIDs, func(s Skill) primitive.ObjectID { return s.ID })
+	r.Companies = orderByIDs(details.Companies, r.CompanyIDs, func(co Company) primitive.ObjectID { return co.ID })
+
+	return nil
+}
+
 // GetAll gets all the role documents
-func (r *Role) GetAll(filters []bson.E) ([]Role, error) {
+func (r *Role) GetAll(filters []bson.E, opts ...*options.FindOptions) ([]Role, error) {
 	roles := make([]Role, 0)
 
-	cursor, err := config.RoleCollection.Find(context.TODO(), bson.D(filters))
+	cursor, err := config.RoleCollection.Find(context.TODO(), bson.D(filters), opts...)
 	if err != nil {
 		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error getting role documents -> %s", err.Error()))
 		return nil, err
@@ -74,3 +183,226 @@ func (r *Role) GetAll(filters []bson.E) ([]Role, error) {
 
 	return roles, nil
 }
+
+// Compare computes the shared and distinct skills, duties and companies of the given roles in a single aggregation
+func (r *Role) Compare(roleIDs []primitive.ObjectID, salaryFilter SalaryFilter) (*RoleComparison, error) {
+	// intersect folds the given array of arrays into the elements common to all of them
+	intersect := func(field string) bson.D {
+		return bson.D{{"$reduce", bson.D{
+			{"input", field},
+			{"initialValue", bson.D{{"$arrayElemAt", bson.A{field, 0}}}},
+			{"in", bson.D{{"$setIntersection", bson.A{"$$value", "$$this"}}}},
+		}}}
+	}
+
+	pipeline := mongo.Pipeline{
+		{{"$match", bson.D{{"_id", bson.D{{"$in", roleIDs}}}}}},
+		{{"$lookup", bson.D{
+			{"from", config.SkillCollection.Name()},
+			{"localField", "skill_ids"},
+			{"foreignField", "_id"},
+			{"as", "skills"},
+		}}},
+		{{"$lookup", bson.D{
+			{"from", config.CompanyCollection.Name()},
+			{"localField", "company_ids"},
+			{"foreignField", "_id"},
+			{"as", "companies"},
+		}}},
+		{{"$project", bson.D{
+			{"name", 1},
+			{"image", 1},
+			{"salaries", bson.D{{"$filter", bson.D{
+				{"input", bson.D{{"$ifNull", bson.A{"$salaries", bson.A{}}}}},
+				{"as", "sal"},
+				{"cond", salaryFilter.Cond("$$sal")},
+			}}}},
+			{"duties", bson.D{{"$ifNull", bson.A{"$duties", bson.A{}}}}},
+			{"companies", bson.D{{"$map", bson.D{
+				{"input", "$companies"},
+				{"as", "co"},
+				{"in", bson.D{{"_id", "$$co._id"}, {"name", "$$co.name"}, {"logo", "$$co.logo"}}},
+			}}}},
+			{"skills", bson.D{{"$map", bson.D{
+				{"input", "$skills"},
+				{"as", "s"},
+				{"in", bson.D{{"_id", "$$s._id"}, {"name", "$$s.name"}, {"image", "$$s.image"}}},
+			}}}},
+		}}},
+		{{"$group", bson.D{
+			{"_id", nil},
+			{"roles", bson.D{{"$push", "$$ROOT"}}},
+		}}},
+		{{"$addFields", bson.D{
+			{"shared_skills", intersect("$roles.skills")},
+			{"shared_duties", intersect("$roles.duties")},
+			{"shared_companies", intersect("$roles.companies")},
+		}}},
+		{{"$project", bson.D{
+			{"_id", 0},
+			{"shared_skills", 1},
+			{"shared_duties", 1},
+			{"shared_companies", 1},
+			{"roles", bson.D{{"$map", bson.D{
+				{"input", "$roles"},
+				{"as", "r"},
+				{"in", bson.D{
+					{"_id", "$$r._id"},
+					{"name", "$$r.name"},
+					{"image", "$$r.image"},
+					{"salaries", "$$r.salaries"},
+					{"distinct_skills", bson.D{{"$setDifference", bson.A{"$$r.skills", "$shared_skills"}}}},
+					{"distinct_duties", bson.D{{"$setDifference", bson.A{"$$r.duties", "$shared_duties"}}}},
+					{"distinct_companies", bson.D{{"$setDifference", bson.A{"$$r.companies", "$shared_companies"}}}},
+				}},
+			}}}},
+		}}},
+	}
+
+	cursor, err := config.RoleCollection.Aggregate(context.TODO(), pipeline)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error aggregating role comparison -> %s", err.Error()))
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	if !cursor.Next(context.TODO()) {
+		if cursor.Err() != nil {
+			logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error reading role comparison cursor -> %s", cursor.Err().Error()))
+			return nil, cursor.Err()
+		}
+
+		return nil, mongo.ErrNoDocuments
+	}
+
+	var comparison RoleComparison
+
+	err = cursor.Decode(&comparison)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error decoding role comparison -> %s", err.Error()))
+		return nil, err
+	}
+
+	return &comparison, nil
+}
+
+// MigrateSalaries converts the legacy free-text salary of the role documents into structured salaries where parseable,
+// keeping the text of the ones that fail to parse so they still show a salary
+func (r *Role) MigrateSalaries() error {
+	filter := bson.D{{"salary", bson.D{{"$type", "string"}}}}
+
+	cursor, err := config.RoleCollection.Find(context.TODO(), filter, options.Find().SetProjection(bson.D{{"salary", 1}}))
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error getting role documents with legacy salary -> %s", err.Error()))
+		return err
+	}
+	defer cursor.Close(context.TODO())
+
+	for cursor.Next(context.TODO()) {
+		var legacy struct {
+			ID     primitive.ObjectID `bson:"_id"`
+			Salary string             `bson:"salary"`
+		}
+
+		err = cursor.Decode(&legacy)
+		if err != nil {
+			logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error decoding legacy salary -> %s", err.Error()))
+			return err
+		}
+
+		min, max, currency, period, ok := utils.ParseSalaryRange(legacy.Salary)
+		if !ok {
+			logging.Logger.Warning(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Unable to parse salary [%s] of role [%s]... Skipping migration", legacy.Salary, legacy.ID.Hex()))
+			continue
+		}
+
+		salary := Salary{
+			Min:      min,
+			Max:      max,
+			Currency: currency,
+			Period:   period,
+			Source:   config.SalarySourceLegacy,
+		}
+
+		update := bson.D{
+			{"$set", bson.D{{"salaries", []Salary{salary}}}},
+			{"$unset", bson.D{{"salary", ""}}},
+		}
+
+		_, err = config.RoleCollection.UpdateByID(context.TODO(), legacy.ID, update)
+		if err != nil {
+			logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error migrating salary of role [%s] -> %s", legacy.ID.Hex(), err.Error()))
+			return err
+		}
+
+		logging.Logger.Info(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Migrated salary of role [%s]", legacy.ID.Hex()))
+	}
+
+	return cursor.Err()
+}
+
+// IsEmpty checks if none of the salary filters are set
+func (sf SalaryFilter) IsEmpty() bool {
+	return sf == SalaryFilter{}
+}
+
+// ElemMatch builds the query conditions matching a salary element of a role
+func (sf SalaryFilter) ElemMatch() bson.D {
+	conditions := bson.D{}
+
+	if sf.Currency != "" {
+		conditions = append(conditions, bson.E{"currency", sf.Currency})
+	}
+	if sf.Period != "" {
+		conditions = append(conditions, bson.E{"period", sf.Period})
+	}
+	if sf.Region != "" {
+		conditions = append(conditions, bson.E{"region", sf.Region})
+	}
+	if sf.ExperienceLevel != "" {
+		conditions = append(conditions, bson.E{"experience_level", sf.ExperienceLevel})
+	}
+	if sf.Min > 0 {
+		conditions = append(conditions, bson.E{"max", bson.D{{"$gte", sf.Min}}})
+	}
+	if sf.Max > 0 {
+		conditions = append(conditions, bson.E{"min", bson.D{{"$lte", sf.Max}}})
+	}
+
+	return conditions
+}
+
+// Cond builds the aggregation expression matching the given salary variable
+func (sf SalaryFilter) Cond(variable string) bson.D {
+	conditions := bson.A{}
+
+	for _, condition := range sf.ElemMatch() {
+		field := fmt.Sprintf("%s.%s", variable, condition.Key)
+
+		if operator, ok := condition.Value.(bson.D); ok {
+			conditions = append(conditions, bson.D{{operator[0].Key, bson.A{field, operator[0].Value}}})
+		} else {
+			conditions = append(conditions, bson.D{{"$eq", bson.A{field, condition.Value}}})
+		}
+	}
+
+	return bson.D{{"$and", conditions}}
+}
+
+// orderByIDs orders the looked up documents as they are referenced, since $lookup does not preserve the order
+func orderByIDs[T any](docs []T, ids []primitive.ObjectID, idOf func(T) primitive.ObjectID) []T {
+	byID := make(map[primitive.ObjectID]T, len(docs))
+	for _, doc := range docs {
+		byID[idOf(doc)] = doc
+	}
+
+	ordered := make([]T, 0, len(docs))
+	for _, id := range ids {
+		if doc, ok := byID[id]; ok {
+			ordered = append(ordered, doc)
+			delete(byID, id)
+		}
+	}
+
+	return ordered
+}
@@ -0,0 +1,103 @@
+package service_test
+
+import (
+	"career-compass-go/config"
+	"career-compass-go/service"
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"testing"
+)
+
+// seedRole inserts a role linked to the given number of new skills and companies and returns the role ID
+func seedRole(b *testing.B, links int) primitive.ObjectID {
+	b.Helper()
+
+	if testDB == nil {
+		b.Skip("mongo is unreachable at MONGO_URI")
+	}
+
+	role := service.Role{
+		ID:         primitive.NewObjectID(),
+		Name:       fmt.Sprintf("Role with %d links", links),
+		SkillIDs:   make([]primitive.ObjectID, links),
+		CompanyIDs: make([]primitive.ObjectID, links),
+	}
+
+	skillDocs := make([]any, links)
+	companyDocs := make([]any, links)
+
+	for i := 0; i < links; i++ {
+		role.SkillIDs[i] = primitive.NewObjectID()
+		role.CompanyIDs[i] = primitive.NewObjectID()
+
+		skillDocs[i] = service.Skill{ID: role.SkillIDs[i], RoleIDs: []primitive.ObjectID{role.ID}, Name: fmt.Sprintf("Skill %d", i)}
+		companyDocs[i] = service.Company{ID: role.CompanyIDs[i], Name: fmt.Sprintf("Company %d", i)}
+	}
+
+	_, err := config.RoleCollection.InsertOne(context.TODO(), role)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	_, err = config.SkillCollection.InsertMany(context.TODO(), skillDocs)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	_, err = config.CompanyCollection.InsertMany(context.TODO(), companyDocs)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	return role.ID
+}
+
+// benchmarkRoleSizes runs the fetch against roles seeded with a growing number of skills and companies
+func benchmarkRoleSizes(b *testing.B, fetch func(roleID primitive.ObjectID) error) {
+	for _, links := range []int{10, 100} {
+		roleID := seedRole(b, links)
+
+		b.Run(fmt.Sprintf("links=%d", links), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				err := fetch(roleID)
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkRolePerSkillLookups(b *testing.B) {
+	benchmarkRoleSizes(b, func(roleID primitive.ObjectID) error {
+		var role service.Role
+
+		err := role.Get([]bson.E{{"_id", roleID}})
+		if err != nil {
+			return err
+		}
+
+		role.Skills = make([]service.Skill, len(role.SkillIDs))
+		for idx, skillID := range role.SkillIDs {
+			err = role.Skills[idx].Get([]bson.E{{"_id", skillID}})
+			if err != nil {
+				return err
+			}
+		}
+
+		var company service.Company
+
+		role.Companies, err = company.GetAll([]bson.E{{"_id", bson.D{{"$in", role.CompanyIDs}}}})
+		return err
+	})
+}
+
+func BenchmarkRoleGetDetails(b *testing.B) {
+	benchmarkRoleSizes(b, func(roleID primitive.ObjectID) error {
+		var role service.Role
+
+		return role.GetDetails(roleID)
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 	"runtime"
 )
 
@@ -18,10 +19,10 @@ type Skill struct {
 	Name        string               `json:"name" bson:"name"`
 	Image       string               `json:"image" bson:"image"`
 	Description string               `json:"description,omitempty" bson:"description"`
-	Youtube     []string             `json:"youtube,omitempty" bson:"youtube"`
-	Website     []string             `json:"website,omitempty" bson:"website"`
-	Courses     []string             `json:"courses,omitempty" bson:"courses"`
+	Dimension   string               `json:"ratingDimension,omitempty" bson:"rating_dimension,omitempty"`
 	Roles       []Role               `json:"roles,omitempty" bson:"-"`
+	Resources   []Resource           `json:"resources,omitempty" bson:"-"`
+	Bookmarked  bool                 `json:"bookmarked" bson:"-"`
 }
 
 // Create inserts a new skill document
@@ -49,6 +50,64 @@ func (s *Skill) Get(filters []bson.E) error {
 	return nil
 }
 
+// GetDetails gets the skill document with its linked roles and ranked resources in a single aggregation
+func (s *Skill) GetDetails(skillID primitive.ObjectID) error {
+	pipeline := mongo.Pipeline{
+		{{"$match", bson.D{{"_id", skillID}}}},
+		{{"$lookup", bson.D{
+			{"from", config.RoleCollection.Name()},
+			{"localField", "role_ids"},
+			{"foreignField", "_id"},
+			{"pipeline", mongo.Pipeline{{{"$project", bson.D{{"name", 1}, {"image", 1}}}}}},
+			{"as", "roles"},
+		}}},
+		{{"$lookup", bson.D{
+			{"from", config.ResourceCollection.Name()},
+			{"localField", "_id"},
+			{"foreignField", "skill_id"},
+			{"pipeline", mongo.Pipeline{
+				{{"$sort", bson.D{{"rating_average", -1}, {"rating_count", -1}, {"created_at", 1}}}},
+				{{"$project", bson.D{{"ratings", 0}, {"link_checks", 0}}}},
+			}},
+			{"as", "resources"},
+		}}},
+	}
+
+	cursor, err := config.SkillCollection.Aggregate(context.TODO(), pipeline)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error aggregating skill details -> %s", err.Error()))
+		return err
+	}
+	defer cursor.Close(context.TODO())
+
+	if !cursor.Next(context.TODO()) {
+		if cursor.Err() != nil {
+			logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error reading skill details cursor -> %s", cursor.Err().Error()))
+			return cursor.Err()
+		}
+
+		return mongo.ErrNoDocuments
+	}
+
+	var details struct {
+		Skill     `bson:",inline"`
+		Roles     []Role     `bson:"roles"`
+		Resources []Resource `bson:"resources"`
+	}
+
+	err = cursor.Decode(&details)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error decoding skill details -> %s", err.Error()))
+		return err
+	}
+
+	*s = details.Skill
+	s.Roles = orderByIDs(details.Roles, s.RoleIDs, func(r Role) primitive.ObjectID { return r.ID })
+	s.Resources = details.Resources
+
+	return nil
+}
+
 // GetAll gets all the skill documents
 func (s *Skill) GetAll(filters []bson.E) ([]Skill, error) {
 	skills := make([]Skill, 0)
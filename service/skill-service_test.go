@@ -0,0 +1,110 @@
+package service_test
+
+import (
+	"career-compass-go/config"
+	"career-compass-go/service"
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"testing"
+	"time"
+)
+
+// seedSkill inserts a skill linked to the given number of new roles and resources and returns the skill ID
+func seedSkill(b *testing.B, links int) primitive.ObjectID {
+	b.Helper()
+
+	if testDB == nil {
+		b.Skip("mongo is unreachable at MONGO_URI")
+	}
+
+	skill := service.Skill{
+		ID:      primitive.NewObjectID(),
+		Name:    fmt.Sprintf("Skill with %d links", links),
+		RoleIDs: make([]primitive.ObjectID, links),
+	}
+
+	currTime := time.Now()
+	roleDocs := make([]any, links)
+	resourceDocs := make([]any, links)
+
+	for i := 0; i < links; i++ {
+		skill.RoleIDs[i] = primitive.NewObjectID()
+
+		roleDocs[i] = service.Role{ID: skill.RoleIDs[i], SkillIDs: []primitive.ObjectID{skill.ID}, Name: fmt.Sprintf("Role %d", i)}
+		resourceDocs[i] = service.Resource{
+			SkillID:   skill.ID,
+			Title:     fmt.Sprintf("Resource %d", i),
+			URL:       fmt.Sprintf("https://example.com/%d", i),
+			Type:      "article",
+			CreatedAt: currTime,
+			UpdatedAt: currTime,
+		}
+	}
+
+	_, err := config.SkillCollection.InsertOne(context.TODO(), skill)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	_, err = config.RoleCollection.InsertMany(context.TODO(), roleDocs)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	_, err = config.ResourceCollection.InsertMany(context.TODO(), resourceDocs)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	return skill.ID
+}
+
+// benchmarkSkillSizes runs the fetch against skills seeded with a growing number of roles and resources
+func benchmarkSkillSizes(b *testing.B, fetch func(skillID primitive.ObjectID) error) {
+	for _, links := range []int{10, 100} {
+		skillID := seedSkill(b, links)
+
+		b.Run(fmt.Sprintf("links=%d", links), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				err := fetch(skillID)
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkSkillPerRoleLookups(b *testing.B) {
+	benchmarkSkillSizes(b, func(skillID primitive.ObjectID) error {
+		var skill service.Skill
+
+		err := skill.Get([]bson.E{{"_id", skillID}})
+		if err != nil {
+			return err
+		}
+
+		skill.Roles = make([]service.Role, len(skill.RoleIDs))
+		for idx, roleID := range skill.RoleIDs {
+			err = skill.Roles[idx].Get([]bson.E{{"_id", roleID}})
+			if err != nil {
+				return err
+			}
+		}
+
+		var resource service.Resource
+
+		skill.Resources, err = resource.GetRanked([]bson.E{{"skill_id", skillID}})
+		return err
+	})
+}
+
+func BenchmarkSkillGetDetails(b *testing.B) {
+	benchmarkSkillSizes(b, func(skillID primitive.ObjectID) error {
+		var skill service.Skill
+
+		return skill.GetDetails(skillID)
+	})
+}
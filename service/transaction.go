@@ -0,0 +1,28 @@
+package service
+
+import (
+	"career-compass-go/config"
+	"career-compass-go/pkg/logging"
+	"career-compass-go/utils"
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/mongo"
+	"runtime"
+)
+
+// withTransaction runs fn in a transaction, retrying it on transient errors such as write conflicts.
+// Transactions need the MongoDB deployment to be a replica set or sharded cluster
+func withTransaction(fn func(ctx mongo.SessionContext) error) error {
+	session, err := config.MongoClient.StartSession()
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error starting mongo session -> %s", err.Error()))
+		return err
+	}
+	defer session.EndSession(context.TODO())
+
+	_, err = session.WithTransaction(context.TODO(), func(ctx mongo.SessionContext) (any, error) {
+		return nil, fn(ctx)
+	})
+
+	return err
+}
@@ -10,40 +10,65 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 	"runtime"
 	"time"
 )
 
 // User collection schema
 type User struct {
-	ID       primitive.ObjectID `bson:"_id,omitempty"`
-	Username string             `bson:"username"`
-	Email    string             `bson:"email"`
-	Password string             `bson:"password"`
-	Role     string             `bson:"role"`
-	OTP      string             `bson:"otp,omitempty"`
-	ExpireAt time.Time          `bson:"expire_at,omitempty"`
+	ID         primitive.ObjectID `bson:"_id,omitempty"`
+	Username   string             `bson:"username"`
+	Email      string             `bson:"email"`
+	Password   string             `bson:"password"`
+	Role       string             `bson:"role"`
+	OTP        string             `bson:"otp,omitempty"`
+	ExpireAt   time.Time          `bson:"expire_at,omitempty"`
+	Reputation int64              `bson:"reputation"`
+	Badges     []Badge            `bson:"badges,omitempty"`
+
+	Warnings         int64     `bson:"warnings,omitempty"`
+	SuspendedUntil   time.Time `bson:"suspended_until,omitempty"`
+	SuspensionReason string    `bson:"suspension_reason,omitempty"`
+
+	DigestFrequency string    `bson:"digest_frequency,omitempty"`
+	DigestSentAt    time.Time `bson:"digest_sent_at,omitempty"`
+}
+
+// Profile holds the public profile of a user
+type Profile struct {
+	UserID      primitive.ObjectID `json:"userID"`
+	Username    string             `json:"username"`
+	Reputation  int64              `json:"reputation"`
+	Badges      []Badge            `json:"badges"`
+	Reputations []Reputation       `json:"skillReputations"`
+}
+
+// Mention holds a user mentioned in a post's content
+type Mention struct {
+	UserID   primitive.ObjectID `json:"userID" bson:"user_id"`
+	Username string             `json:"username" bson:"username"`
 }
 
 // RatingsData hold the assessment ratings data with ordered fields of a user
 type RatingsData struct {
-	RateWebDev          int `json:"rate_webDev"`
-	RateInfraAutomation int `json:"rate_infraAutomation"`
-	RateMLAlgo          int `json:"rate_mlAlgo"`
-	RateUIUX            int `json:"rate_uiux"`
-	RateBlockchain      int `json:"rate_blockchain"`
-	RateAppDev          int `json:"rate_appDev"`
-	RateCloud           int `json:"rate_cloud"`
-	RateTesting         int `json:"rate_testing"`
-	RateDataAnalytics   int `json:"rate_dataAnalytics"`
-	RateEmbedded        int `json:"rate_embedded"`
-	RateAI              int `json:"rate_ai"`
-	RateCyber           int `json:"rate_cyber"`
-	RateARVR            int `json:"rate_arvr"`
-	RateCompArch        int `json:"rate_compArch"`
-	RateNetwork         int `json:"rate_network"`
-	RateProjectMan      int `json:"rate_projectMan"`
-	RateGame            int `json:"rate_game"`
+	RateWebDev          int `json:"rate_webDev" bson:"rate_webDev"`
+	RateInfraAutomation int `json:"rate_infraAutomation" bson:"rate_infraAutomation"`
+	RateMLAlgo          int `json:"rate_mlAlgo" bson:"rate_mlAlgo"`
+	RateUIUX            int `json:"rate_uiux" bson:"rate_uiux"`
+	RateBlockchain      int `json:"rate_blockchain" bson:"rate_blockchain"`
+	RateAppDev          int `json:"rate_appDev" bson:"rate_appDev"`
+	RateCloud           int `json:"rate_cloud" bson:"rate_cloud"`
+	RateTesting         int `json:"rate_testing" bson:"rate_testing"`
+	RateDataAnalytics   int `json:"rate_dataAnalytics" bson:"rate_dataAnalytics"`
+	RateEmbedded        int `json:"rate_embedded" bson:"rate_embedded"`
+	RateAI              int `json:"rate_ai" bson:"rate_ai"`
+	RateCyber           int `json:"rate_cyber" bson:"rate_cyber"`
+	RateARVR            int `json:"rate_arvr" bson:"rate_arvr"`
+	RateCompArch        int `json:"rate_compArch" bson:"rate_compArch"`
+	RateNetwork         int `json:"rate_network" bson:"rate_network"`
+	RateProjectMan      int `json:"rate_projectMan" bson:"rate_projectMan"`
+	RateGame            int `json:"rate_game" bson:"rate_game"`
 }
 
 // Create inserts a new user document
@@ -71,6 +96,19 @@ func (us *User) Get(filters []bson.E) error {
 	return nil
 }
 
+// GetSuspension finds the user document, loading only its suspension
+func (us *User) GetSuspension(userID primitive.ObjectID) error {
+	opts := options.FindOne().SetProjection(bson.D{{"suspended_until", 1}, {"suspension_reason", 1}})
+
+	err := config.UserCollection.FindOne(context.TODO(), bson.D{{"_id", userID}}, opts).Decode(us)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error finding suspension of user [%s] -> %s", userID.Hex(), err.Error()))
+		return err
+	}
+
+	return nil
+}
+
 // Update updates the user document based on the given update query
 func (us *User) Update(filters []bson.E, update bson.D) error {
 	_, err := config.UserCollection.UpdateOne(context.TODO(), bson.D(filters), update)
@@ -96,3 +134,26 @@ func (us *User) CheckExistingUser() (bool, error) {
 
 	return true, nil
 }
+
+// GetByUsernames gets the user documents whose username matches one of the given names, ignoring case
+func (us *User) GetByUsernames(usernames []string) ([]User, error) {
+	users := make([]User, 0)
+
+	opts := options.Find().
+		SetCollation(&options.Collation{Locale: "en", Strength: 2}).
+		SetProjection(bson.D{{"username", 1}})
+
+	cursor, err := config.UserCollection.Find(context.TODO(), bson.D{{"username", bson.D{{"$in", usernames}}}}, opts)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error finding user documents by username -> %s", err.Error()))
+		return nil, err
+	}
+
+	err = cursor.All(context.TODO(), &users)
+	if err != nil {
+		logging.Logger.Error(utils.GetFrame(runtime.Caller(0)), fmt.Sprintf("Error decoding user documents from cursor -> %s", err.Error()))
+		return nil, err
+	}
+
+	return users, nil
+}
@@ -1,20 +1,35 @@
 package utils
 
 import (
+	"bytes"
 	"career-compass-go/config"
 	"career-compass-go/pkg/logging"
+	"context"
 	"crypto/rand"
 	"encoding/json"
 	"fmt"
 	"golang.org/x/crypto/bcrypt"
-	"io"
 	"net/http"
 	"net/url"
 	"path/filepath"
+	"regexp"
 	"runtime"
+	"strconv"
 	"strings"
 )
 
+var (
+	restClient = &http.Client{Timeout: config.RestClientTimeout}
+
+	salaryAmountRegex  = regexp.MustCompile(`(?i)(\d+(?:[.,]\d+)*)\s*(lpa|lakhs?|lacs?|l|k|crores?|cr|m)?\b`)
+	salaryINRRegex     = regexp.MustCompile(`(?i)₹|\b(inr|rs\.?|lpa|lakhs?|lacs?)\b`)
+	salaryEURRegex     = regexp.MustCompile(`(?i)€|\beur\b`)
+	salaryGBPRegex     = regexp.MustCompile(`(?i)£|\bgbp\b`)
+	salaryUSDRegex     = regexp.MustCompile(`(?i)\$|\busd\b`)
+	salaryHourlyRegex  = regexp.MustCompile(`(?i)\bhour(ly)?\b|/\s*h(ou)?r\b`)
+	salaryMonthlyRegex = regexp.MustCompile(`(?i)\bmonth(ly)?\b|/\s*mo(nth)?\b|\bp\.?m\.?\b`)
+)
+
 // GetFrame returns a formatted string representing the frame of the call
 func GetFrame(function uintptr, file string, line int, _ bool) string {
 	absPath, _ := filepath.Rel(strings.Split(file, "career-compass-go")[0]+"career-compass-go", file)
@@ -62,28 +77,31 @@ func VerifyPasswordHash(password, hash string) bool {
 	return err == nil
 }
 
-// RestPost makes a REST post request to the given host
-func RestPost(host string, reqData any) (*http.Response, error) {
-	client := &http.Client{}
-
-	reqURL, _ := url.Parse(host)
+// RestPost makes a REST post request to the given host, bounded by the context
+func RestPost(ctx context.Context, host string, reqData any) (*http.Response, error) {
+	reqURL, err := url.Parse(host)
+	if err != nil {
+		logging.Logger.Error(GetFrame(runtime.Caller(0)), fmt.Sprintf("Error parsing URL %s -> %s", host, err.Error()))
+		return nil, err
+	}
 
-	jsonData, _ := json.Marshal(reqData)
+	jsonData, err := json.Marshal(reqData)
+	if err != nil {
+		logging.Logger.Error(GetFrame(runtime.Caller(0)), fmt.Sprintf("Error marshalling payload for %s -> %s", reqURL, err.Error()))
+		return nil, err
+	}
 
 	logging.Logger.Debug(GetFrame(runtime.Caller(0)), fmt.Sprintf("URL -> %s \nPayload -> %s", reqURL, string(jsonData)))
 
-	reqBody := io.NopCloser(strings.NewReader(string(jsonData)))
-
-	req := &http.Request{
-		Method: http.MethodPost,
-		URL:    reqURL,
-		Header: make(http.Header),
-		Body:   reqBody,
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, reqURL.String(), bytes.NewReader(jsonData))
+	if err != nil {
+		logging.Logger.Error(GetFrame(runtime.Caller(0)), fmt.Sprintf("Error creating POST request to %s -> %s", reqURL, err.Error()))
+		return nil, err
 	}
 
 	req.Header.Set("Content-Type", "application/json")
 
-	res, err := client.Do(req)
+	res, err := restClient.Do(req)
 	if err != nil {
 		logging.Logger.Error(GetFrame(runtime.Caller(0)), fmt.Sprintf("Error making POST request to %s -> %s", reqURL, err.Error()))
 		return nil, err
@@ -91,3 +109,90 @@ func RestPost(host string, reqData any) (*http.Response, error) {
 
 	return res, nil
 }
+
+// ParseSalaryRange extracts the range, currency and period from a free-text salary such as "₹6 - 10 LPA" or "$80,000 - $120,000 per year"
+func ParseSalaryRange(text string) (min, max float64, currency, period string, ok bool) {
+	switch {
+	case salaryINRRegex.MatchString(text):
+		currency = "INR"
+	case salaryEURRegex.MatchString(text):
+		currency = "EUR"
+	case salaryGBPRegex.MatchString(text):
+		currency = "GBP"
+	case salaryUSDRegex.MatchString(text):
+		currency = "USD"
+	default:
+		return 0, 0, "", "", false
+	}
+
+	switch {
+	case salaryHourlyRegex.MatchString(text):
+		period = config.SalaryPeriodHourly
+	case salaryMonthlyRegex.MatchString(text):
+		period = config.SalaryPeriodMonthly
+	default:
+		period = config.SalaryPeriodYearly
+	}
+
+	matches := salaryAmountRegex.FindAllStringSubmatch(text, 2)
+	if len(matches) == 0 {
+		return 0, 0, "", "", false
+	}
+
+	amounts := make([]float64, len(matches))
+	multipliers := make([]float64, len(matches))
+
+	for idx, match := range matches {
+		amount, err := strconv.ParseFloat(strings.ReplaceAll(match[1], ",", ""), 64)
+		if err != nil {
+			return 0, 0, "", "", false
+		}
+
+		amounts[idx] = amount
+
+		switch strings.ToLower(match[2]) {
+		case "lpa", "lakh", "lakhs", "lac", "lacs", "l":
+			multipliers[idx] = 100000
+		case "k":
+			multipliers[idx] = 1000
+		case "cr", "crore", "crores":
+			multipliers[idx] = 10000000
+		case "m":
+			multipliers[idx] = 1000000
+		default:
+			multipliers[idx] = 1
+		}
+	}
+
+	// A unit written only after the upper bound ("6 - 10 LPA") applies to the lower bound as well
+	if len(matches) == 2 && multipliers[0] == 1 {
+		multipliers[0] = multipliers[1]
+	}
+
+	min = amounts[0] * multipliers[0]
+	max = amounts[len(amounts)-1] * multipliers[len(multipliers)-1]
+
+	if min > max {
+		min, max = max, min
+	}
+
+	return min, max, currency, period, true
+}
+
+// NormalizeTags lowercases and hyphenates the tags, dropping blanks and duplicates
+func NormalizeTags(tags []string) []string {
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool)
+
+	for _, tag := range tags {
+		tag = strings.Join(strings.Fields(strings.ToLower(tag)), "-")
+		if tag == "" || seen[tag] {
+			continue
+		}
+
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+
+	return normalized
+}
@@ -0,0 +1,48 @@
+package utils
+
+import (
+	"career-compass-go/config"
+	"testing"
+)
+
+func TestParseSalaryRange(t *testing.T) {
+	tests := []struct {
+		text     string
+		min      float64
+		max      float64
+		currency string
+		period   string
+		ok       bool
+	}{
+		{"₹6 - 10 LPA", 600000, 1000000, "INR", config.SalaryPeriodYearly, true},
+		{"6-10 lakhs per annum", 600000, 1000000, "INR", config.SalaryPeriodYearly, true},
+		{"Rs. 4.5 L - 7 L", 450000, 700000, "INR", config.SalaryPeriodYearly, true},
+		{"₹1 - 1.5 crores", 10000000, 15000000, "INR", config.SalaryPeriodYearly, true},
+		{"INR 50,000 per month", 50000, 50000, "INR", config.SalaryPeriodMonthly, true},
+		{"$80,000 - $120,000 per year", 80000, 120000, "USD", config.SalaryPeriodYearly, true},
+		{"$80k - $120k", 80000, 120000, "USD", config.SalaryPeriodYearly, true},
+		{"USD 1.2M", 1200000, 1200000, "USD", config.SalaryPeriodYearly, true},
+		{"$45/hr", 45, 45, "USD", config.SalaryPeriodHourly, true},
+		{"€40,000 - €55,000", 40000, 55000, "EUR", config.SalaryPeriodYearly, true},
+		{"£3,000 - 4,000 monthly", 3000, 4000, "GBP", config.SalaryPeriodMonthly, true},
+		{"$120k - $90k", 90000, 120000, "USD", config.SalaryPeriodYearly, true},
+		{"Competitive", 0, 0, "", "", false},
+		{"10 - 20 LPA", 1000000, 2000000, "INR", config.SalaryPeriodYearly, true},
+		{"$ negotiable", 0, 0, "", "", false},
+		{"", 0, 0, "", "", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.text, func(t *testing.T) {
+			min, max, currency, period, ok := ParseSalaryRange(test.text)
+			if ok != test.ok {
+				t.Fatalf("ParseSalaryRange(%q) ok = %v, want %v", test.text, ok, test.ok)
+			}
+
+			if min != test.min || max != test.max || currency != test.currency || period != test.period {
+				t.Errorf("ParseSalaryRange(%q) = %v, %v, %q, %q, want %v, %v, %q, %q",
+					test.text, min, max, currency, period, test.min, test.max, test.currency, test.period)
+			}
+		})
+	}
+}